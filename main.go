@@ -1,18 +1,32 @@
 package main
 
 import (
+	"flag"
 	"log"
 	"os"
+	"path/filepath"
 
 	"github.com/sjsanc/golox/tw"
 )
 
 func main() {
-	if len(os.Args) > 1 {
-		log.Println("Usage: golox [script]")
+	maxCallDepth := flag.Int("max-call-depth", tw.DefaultMaxCallDepth, "maximum nesting of Lox calls (0 for no limit)")
+	searchPath := flag.String("path", os.Getenv("LOX_PATH"), "list of directories to search for imported modules")
+	flag.Parse()
+
+	if flag.NArg() > 1 {
+		log.Println("Usage: golox [-max-call-depth n] [-path dirs] [script]")
 		os.Exit(64)
 	}
 
-	program := tw.NewProgram()
-	program.RunFile("test.lox")
+	script := "test.lox"
+	if flag.NArg() == 1 {
+		script = flag.Arg(0)
+	}
+
+	program := tw.NewProgram(
+		tw.WithMaxCallDepth(*maxCallDepth),
+		tw.WithSearchPath(filepath.SplitList(*searchPath)...),
+	)
+	program.RunFile(script)
 }
@@ -0,0 +1,32 @@
+#!/bin/sh
+# Runs every .lox script under tests/ and compares its output with the
+# `// expect: ...` comments in the script. Scripts under a lib/ directory
+# are only imported by other tests.
+
+set -u
+
+root=$(cd "$(dirname "$0")/.." && pwd)
+bin=$(mktemp -d)/golox
+trap 'rm -rf "$(dirname "$bin")"' EXIT
+
+(cd "$root" && go build -o "$bin" .) || exit 1
+
+pass=0
+fail=0
+for script in $(find "$root/tests" -name '*.lox' -not -path '*/lib/*' | sort); do
+	expected=$(sed -n 's|.*// expect: ||p' "$script")
+	actual=$("$bin" "$script" 2>/dev/null)
+	if [ "$expected" = "$actual" ]; then
+		pass=$((pass + 1))
+	else
+		fail=$((fail + 1))
+		echo "FAIL ${script#$root/}"
+		echo "  expected:"
+		echo "$expected" | sed 's/^/    /'
+		echo "  actual:"
+		echo "$actual" | sed 's/^/    /'
+	fi
+done
+
+echo "$pass passed, $fail failed"
+[ "$fail" -eq 0 ]
@@ -2,18 +2,46 @@ package tw
 
 import "time"
 
-type ClockBuiltin struct {
-	arity int
-}
+type ClockBuiltin struct{}
 
-func (c *ClockBuiltin) Arity() int {
-	return c.arity
+func (c *ClockBuiltin) Arity() (int, int) {
+	return 0, 0
 }
 
-func (c *ClockBuiltin) Call(interpreter *Interpreter, args []interface{}) interface{} {
-	return time.Now().Unix()
+func (c *ClockBuiltin) Call(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+	return int(time.Now().Unix()), nil
 }
 
 func (c *ClockBuiltin) String() string {
 	return "<native fn>"
 }
+
+// NativeFunction wraps a Go function so it can be called from Lox, such as
+// the methods exposed by built-in values.
+type NativeFunction struct {
+	name string
+	min  int
+	max  int
+	fn   func(interpreter *Interpreter, args []interface{}) (interface{}, error)
+}
+
+func NewNativeFunction(name string, min, max int, fn func(interpreter *Interpreter, args []interface{}) (interface{}, error)) *NativeFunction {
+	return &NativeFunction{
+		name: name,
+		min:  min,
+		max:  max,
+		fn:   fn,
+	}
+}
+
+func (n *NativeFunction) Arity() (int, int) {
+	return n.min, n.max
+}
+
+func (n *NativeFunction) Call(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+	return n.fn(interpreter, args)
+}
+
+func (n *NativeFunction) String() string {
+	return "<native fn " + n.name + ">"
+}
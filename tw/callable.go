@@ -1,6 +1,14 @@
 package tw
 
+// VariadicArity is returned as the maximum arity of callables that accept
+// any number of trailing arguments.
+const VariadicArity = -1
+
 type Callable interface {
-	Arity() int
+	Arity() (min int, max int)
 	Call(interpreter *Interpreter, args []interface{}) (interface{}, error)
 }
+
+// missingArgument fills the positional slots skipped over by keyword
+// arguments, so the callee knows to fall back to the parameter's default.
+type missingArgument struct{}
@@ -1,17 +1,66 @@
 package tw
 
+import "fmt"
+
+// Class is also an object in its own right: its static methods live on its
+// metaclass, and class-level variables are stored in fields.
 type Class struct {
 	name       string
 	superclass *Class
+	metaclass  *Class
 	methods    map[string]*Function
+	setters    map[string]*Function
+	fields     map[string]interface{}
+	// abstract holds the required methods the class leaves unimplemented.
+	abstract map[string]*FunctionStmt
+	// declared holds the instance fields declared in the class body, which
+	// are evaluated in closure for every new instance.
+	declared []*VarStmt
+	closure  *Environment
+	// record lists the components of a record class, which are compared by
+	// value rather than by identity.
+	record []string
 }
 
-func NewClass(name string, superclass *Class, methods map[string]*Function) *Class {
+func NewClass(name string, superclass *Class, metaclass *Class, methods map[string]*Function, setters map[string]*Function) *Class {
 	return &Class{
 		name:       name,
 		superclass: superclass,
+		metaclass:  metaclass,
 		methods:    methods,
+		setters:    setters,
+		fields:     make(map[string]interface{}),
+		abstract:   make(map[string]*FunctionStmt),
+	}
+}
+
+// NewMetaclass creates the metaclass holding the static methods of a class.
+// It inherits from the metaclass of the superclass, if there is one.
+func NewMetaclass(name string, superclass *Class, methods map[string]*Function) *Class {
+	var supermeta *Class
+	if superclass != nil {
+		supermeta = superclass.metaclass
+	}
+	return NewClass(name+" metaclass", supermeta, nil, methods, nil)
+}
+
+func (c *Class) Get(interpreter *Interpreter, name *Token) (interface{}, error) {
+	for class := c; class != nil; class = class.superclass {
+		if value, ok := class.fields[name.lexeme]; ok {
+			return value, nil
+		}
+	}
+	if c.metaclass != nil {
+		if method := c.metaclass.FindMethod(name.lexeme); method != nil {
+			return method, nil
+		}
 	}
+	return nil, fmt.Errorf("undefined property '%s'", name.lexeme)
+}
+
+func (c *Class) Set(interpreter *Interpreter, name *Token, value interface{}) error {
+	c.fields[name.lexeme] = value
+	return nil
 }
 
 func (c *Class) FindMethod(name string) *Function {
@@ -24,23 +73,89 @@ func (c *Class) FindMethod(name string) *Function {
 	return nil
 }
 
+// initFields evaluates the declared fields of c and its superclasses on a new
+// instance, starting from the root of the hierarchy.
+func (c *Class) initFields(interpreter *Interpreter, instance *Instance) error {
+	if c.superclass != nil {
+		if err := c.superclass.initFields(interpreter, instance); err != nil {
+			return err
+		}
+	}
+	if len(c.declared) == 0 {
+		return nil
+	}
+	env := NewEnvironment(c.closure)
+	env.Define("this", instance)
+	enclosing := interpreter.class
+	interpreter.class = c
+	defer func() { interpreter.class = enclosing }()
+	for _, field := range c.declared {
+		var value interface{}
+		if field.initializer != nil {
+			v, err := interpreter.evaluateIn(field.initializer, env)
+			if err != nil {
+				return err
+			}
+			value = v
+		}
+		instance.define(c, field.name.lexeme, value)
+	}
+	return nil
+}
+
+// inherits reports whether c is other or one of its subclasses.
+func (c *Class) inherits(other *Class) bool {
+	for class := c; class != nil; class = class.superclass {
+		if class == other {
+			return true
+		}
+	}
+	return false
+}
+
+// implements checks that method accepts the arguments of the signature it
+// implements.
+func implements(method *Function, signature *FunctionStmt) bool {
+	min, max := method.Arity()
+	n := len(signature.params)
+	return min <= n && (max == VariadicArity || max >= n)
+}
+
+func (c *Class) FindSetter(name string) *Function {
+	if setter, ok := c.setters[name]; ok {
+		return setter
+	}
+	if c.superclass != nil {
+		return c.superclass.FindSetter(name)
+	}
+	return nil
+}
+
 func (c *Class) String() string {
 	return c.name
 }
 
-func (c *Class) Arity() int {
+func (c *Class) Arity() (int, int) {
 	initializer := c.FindMethod("init")
 	if initializer != nil {
 		return initializer.Arity()
 	}
-	return 0
+	return 0, 0
 }
 
+// Call creates an instance. Abstract classes are rejected by
+// Interpreter.call, which every call goes through.
 func (c *Class) Call(interpreter *Interpreter, args []interface{}) (interface{}, error) {
 	instance := NewInstance(c)
+	if err := c.initFields(interpreter, instance); err != nil {
+		return nil, err
+	}
 	initializer := c.FindMethod("init")
 	if initializer != nil {
-		initializer.Bind(instance).Call(interpreter, args)
+		if _, err := interpreter.call(initializer.declaration.name, initializer.Bind(instance), args); err != nil {
+			return nil, err
+		}
 	}
+	instance.frozen = c.record != nil
 	return instance, nil
 }
@@ -0,0 +1,84 @@
+package tw
+
+import "fmt"
+
+// Enum is the type created by an enum declaration. Its members are
+// singletons, ordered by declaration.
+type Enum struct {
+	name    string
+	members []*EnumMember
+}
+
+type EnumMember struct {
+	enum    *Enum
+	name    string
+	ordinal int
+	value   interface{}
+}
+
+func NewEnum(name string) *Enum {
+	return &Enum{
+		name:    name,
+		members: make([]*EnumMember, 0),
+	}
+}
+
+func (e *Enum) add(name string, value interface{}) {
+	e.members = append(e.members, &EnumMember{
+		enum:    e,
+		name:    name,
+		ordinal: len(e.members),
+		value:   value,
+	})
+}
+
+func (e *Enum) Get(interpreter *Interpreter, name *Token) (interface{}, error) {
+	for _, member := range e.members {
+		if member.name == name.lexeme {
+			return member, nil
+		}
+	}
+	return nil, fmt.Errorf("undefined member '%s' of enum %s", name.lexeme, e.name)
+}
+
+func (e *Enum) Iterator() Iterator {
+	elements := make([]interface{}, len(e.members))
+	for k, member := range e.members {
+		elements[k] = member
+	}
+	return &sliceIterator{elements: elements}
+}
+
+func (e *Enum) String() string {
+	return "<enum " + e.name + ">"
+}
+
+func (m *EnumMember) Get(interpreter *Interpreter, name *Token) (interface{}, error) {
+	switch name.lexeme {
+	case "name":
+		return m.name, nil
+	case "ordinal":
+		return m.ordinal, nil
+	case "value":
+		return m.value, nil
+	}
+	return nil, fmt.Errorf("undefined property '%s'", name.lexeme)
+}
+
+func (m *EnumMember) String() string {
+	return m.enum.name + "." + m.name
+}
+
+// enumOrdinals returns the ordinals of two members of the same enum, so they
+// can be compared like numbers.
+func enumOrdinals(left, right interface{}) (int, int, bool) {
+	a, ok := left.(*EnumMember)
+	if !ok {
+		return 0, 0, false
+	}
+	b, ok := right.(*EnumMember)
+	if !ok || a.enum != b.enum {
+		return 0, 0, false
+	}
+	return a.ordinal, b.ordinal, true
+}
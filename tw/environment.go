@@ -5,6 +5,7 @@ import "fmt"
 type Environment struct {
 	enclosing *Environment
 	values    map[string]interface{}
+	constants map[string]bool
 }
 
 func NewGlobalEnvironment() *Environment {
@@ -20,8 +21,33 @@ func NewEnvironment(enclosing *Environment) *Environment {
 	}
 }
 
-func (e *Environment) Define(name string, value interface{}) {
+// global returns the outermost environment of the chain, which holds the
+// globals of the module the code belongs to.
+func (e *Environment) global() *Environment {
+	for e.enclosing != nil {
+		e = e.enclosing
+	}
+	return e
+}
+
+// Define binds name in this environment. A constant can't be redefined.
+func (e *Environment) Define(name string, value interface{}) error {
+	if e.constants[name] {
+		return fmt.Errorf("cannot redeclare constant '%s'", name)
+	}
 	e.values[name] = value
+	return nil
+}
+
+func (e *Environment) DefineConstant(name string, value interface{}) error {
+	if err := e.Define(name, value); err != nil {
+		return err
+	}
+	if e.constants == nil {
+		e.constants = make(map[string]bool)
+	}
+	e.constants[name] = true
+	return nil
 }
 
 func (e *Environment) Get(name *Token) (interface{}, error) {
@@ -44,6 +70,9 @@ func (e *Environment) GetAt(distance int, name string) (interface{}, error) {
 
 func (e *Environment) Assign(name *Token, value interface{}) error {
 	if _, ok := e.values[name.lexeme]; ok {
+		if e.constants[name.lexeme] {
+			return fmt.Errorf("cannot assign to constant '%s'", name.lexeme)
+		}
 		e.values[name.lexeme] = value
 		return nil
 	}
@@ -55,6 +84,9 @@ func (e *Environment) Assign(name *Token, value interface{}) error {
 
 func (e *Environment) AssignAt(distance int, name *Token, value interface{}) error {
 	env := e.ancestor(distance)
+	if env.constants[name.lexeme] {
+		return fmt.Errorf("cannot assign to constant '%s'", name.lexeme)
+	}
 	env.values[name.lexeme] = value
 	return nil
 }
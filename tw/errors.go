@@ -1,6 +1,28 @@
 package tw
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var ErrCompiler = errors.New("compiler error")
 var ErrRuntime = errors.New("runtime error")
+
+// RuntimeError is an error raised while interpreting, reported at the
+// location of the token where it happened.
+type RuntimeError struct {
+	token   *Token
+	message string
+}
+
+func (e *RuntimeError) Error() string {
+	return fmt.Sprintf("[%s] Error: %s", e.token.location(), e.message)
+}
+
+// errShortCircuit unwinds an optional chain to its OptionalChainExpr when a
+// `?.` meets nil. It never escapes the interpreter.
+var errShortCircuit = errors.New("optional chain short-circuit")
+
+// errGeneratorClosed unwinds the body of a generator parked at a yield when
+// the generator is closed.
+var errGeneratorClosed = errors.New("generator closed")
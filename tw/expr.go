@@ -8,13 +8,24 @@ type ExprVisitor interface {
 	visitAssignExpr(expr *AssignExpr) (interface{}, error)
 	visitBinaryExpr(expr *BinaryExpr) (interface{}, error)
 	visitCallExpr(expr *CallExpr) (interface{}, error)
+	visitClassOfExpr(expr *ClassOfExpr) (interface{}, error)
+	visitDestructureExpr(expr *DestructureExpr) (interface{}, error)
 	visitGetExpr(expr *GetExpr) (interface{}, error)
 	visitGroupingExpr(expr *GroupingExpr) (interface{}, error)
+	visitIndexExpr(expr *IndexExpr) (interface{}, error)
+	visitIndexSetExpr(expr *IndexSetExpr) (interface{}, error)
+	visitListExpr(expr *ListExpr) (interface{}, error)
 	visitLiteralExpr(expr *LiteralExpr) (interface{}, error)
 	visitLogicalExpr(expr *LogicalExpr) (interface{}, error)
+	visitMapExpr(expr *MapExpr) (interface{}, error)
+	visitOptionalChainExpr(expr *OptionalChainExpr) (interface{}, error)
+	visitRangeExpr(expr *RangeExpr) (interface{}, error)
 	visitSetExpr(expr *SetExpr) (interface{}, error)
+	visitSliceExpr(expr *SliceExpr) (interface{}, error)
+	visitSpreadExpr(expr *SpreadExpr) (interface{}, error)
 	visitSuperExpr(expr *SuperExpr) (interface{}, error)
 	visitThisExpr(expr *ThisExpr) (interface{}, error)
+	visitTupleExpr(expr *TupleExpr) (interface{}, error)
 	visitUnaryExpr(expr *UnaryExpr) (interface{}, error)
 	visitVariableExpr(expr *VariableExpr) (interface{}, error)
 }
@@ -54,19 +65,62 @@ type CallExpr struct {
 	callee Expr
 	paren  *Token
 	args   []Expr
+	kwargs []*KeywordArg
 }
 
 func (expr *CallExpr) Accept(v ExprVisitor) (interface{}, error) {
 	return v.visitCallExpr(expr)
 }
 
+// ================================================================================
+// ### CLASS OF
+// ================================================================================
+
+// ClassOfExpr evaluates to the class of an instance. It has no syntax of its
+// own; the parser generates it for the with() method of records.
+type ClassOfExpr struct {
+	object Expr
+}
+
+func (expr *ClassOfExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.visitClassOfExpr(expr)
+}
+
+type KeywordArg struct {
+	name  *Token
+	value Expr
+}
+
+// ================================================================================
+// ### DESTRUCTURE
+// ================================================================================
+
+type DestructureExpr struct {
+	pattern *Pattern
+	equals  *Token
+	value   Expr
+}
+
+func (expr *DestructureExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.visitDestructureExpr(expr)
+}
+
+// Pattern is the target of a destructuring declaration or assignment. An
+// opening '(' or '[' unpacks a tuple or list by position, and '{' unpacks
+// the fields of an instance by name.
+type Pattern struct {
+	open    *Token
+	targets []*VariableExpr
+}
+
 // ================================================================================
 // ### GET
 // ================================================================================
 
 type GetExpr struct {
-	object Expr
-	name   *Token
+	object   Expr
+	name     *Token
+	optional bool
 }
 
 func (expr *GetExpr) Accept(v ExprVisitor) (interface{}, error) {
@@ -85,6 +139,48 @@ func (expr *GroupingExpr) Accept(v ExprVisitor) (interface{}, error) {
 	return v.visitGroupingExpr(expr)
 }
 
+// ================================================================================
+// ### INDEX
+// ================================================================================
+
+type IndexExpr struct {
+	object  Expr
+	bracket *Token
+	index   Expr
+}
+
+func (expr *IndexExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.visitIndexExpr(expr)
+}
+
+// ================================================================================
+// ### INDEX SET
+// ================================================================================
+
+type IndexSetExpr struct {
+	object  Expr
+	bracket *Token
+	index   Expr
+	value   Expr
+}
+
+func (expr *IndexSetExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.visitIndexSetExpr(expr)
+}
+
+// ================================================================================
+// ### LIST
+// ================================================================================
+
+type ListExpr struct {
+	bracket  *Token
+	elements []Expr
+}
+
+func (expr *ListExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.visitListExpr(expr)
+}
+
 // ================================================================================
 // ### LITERAL
 // ================================================================================
@@ -111,6 +207,49 @@ func (expr *LogicalExpr) Accept(v ExprVisitor) (interface{}, error) {
 	return v.visitLogicalExpr(expr)
 }
 
+// ================================================================================
+// ### MAP
+// ================================================================================
+
+type MapExpr struct {
+	brace  *Token
+	keys   []Expr
+	values []Expr
+}
+
+func (expr *MapExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.visitMapExpr(expr)
+}
+
+// ================================================================================
+// ### OPTIONAL CHAIN
+// ================================================================================
+
+// OptionalChainExpr wraps a chain of property accesses and calls containing
+// at least one `?.`, and evaluates to nil when any `?.` meets nil.
+type OptionalChainExpr struct {
+	expr Expr
+}
+
+func (expr *OptionalChainExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.visitOptionalChainExpr(expr)
+}
+
+// ================================================================================
+// ### RANGE
+// ================================================================================
+
+type RangeExpr struct {
+	start    Expr
+	operator *Token
+	stop     Expr
+	step     Expr
+}
+
+func (expr *RangeExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.visitRangeExpr(expr)
+}
+
 // ================================================================================
 // ### SET
 // ================================================================================
@@ -125,6 +264,37 @@ func (expr *SetExpr) Accept(v ExprVisitor) (interface{}, error) {
 	return v.visitSetExpr(expr)
 }
 
+// ================================================================================
+// ### SLICE
+// ================================================================================
+
+type SliceExpr struct {
+	object  Expr
+	bracket *Token
+	start   Expr
+	stop    Expr
+	step    Expr
+}
+
+func (expr *SliceExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.visitSliceExpr(expr)
+}
+
+// ================================================================================
+// ### SPREAD
+// ================================================================================
+
+// SpreadExpr only appears as a call argument, a list element or a map entry,
+// where its value is expanded in place.
+type SpreadExpr struct {
+	ellipsis *Token
+	expr     Expr
+}
+
+func (expr *SpreadExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.visitSpreadExpr(expr)
+}
+
 // ================================================================================
 // ### SUPER
 // ================================================================================
@@ -150,6 +320,19 @@ func (expr *ThisExpr) Accept(v ExprVisitor) (interface{}, error) {
 	return v.visitThisExpr(expr)
 }
 
+// ================================================================================
+// ### TUPLE
+// ================================================================================
+
+type TupleExpr struct {
+	paren    *Token
+	elements []Expr
+}
+
+func (expr *TupleExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.visitTupleExpr(expr)
+}
+
 // ================================================================================
 // ### UNARY
 // ================================================================================
@@ -4,10 +4,20 @@ import (
 	"fmt"
 )
 
+// tailCall is returned by a ReturnStmt whose value is a call to a Lox
+// function, so that Function.Call can run it in the same Go stack frame.
+type tailCall struct {
+	function *Function
+	args     []interface{}
+}
+
 type Function struct {
 	declaration   *FunctionStmt
 	closure       *Environment
 	isInitializer bool
+	// class is the class whose body declared the function, if any. It
+	// decides which private members the function can access.
+	class *Class
 }
 
 func NewFunction(declaration *FunctionStmt, closure *Environment, isInitializer bool) *Function {
@@ -21,32 +31,128 @@ func NewFunction(declaration *FunctionStmt, closure *Environment, isInitializer
 func (f *Function) Bind(instance *Instance) *Function {
 	env := NewEnvironment(f.closure)
 	env.Define("this", instance)
-	return NewFunction(f.declaration, env, f.isInitializer)
+	bound := NewFunction(f.declaration, env, f.isInitializer)
+	bound.class = f.class
+	return bound
 }
 
-func (f *Function) Arity() int {
-	return len(f.declaration.params)
+func (f *Function) Arity() (int, int) {
+	min := 0
+	for _, value := range f.declaration.defaults {
+		if value == nil {
+			min++
+		}
+	}
+	if f.declaration.rest != nil {
+		return min, VariadicArity
+	}
+	return min, len(f.declaration.params)
 }
 
 func (f *Function) Call(interpreter *Interpreter, args []interface{}) (interface{}, error) {
-	env := NewEnvironment(f.closure)
-	for i, param := range f.declaration.params {
-		env.Define(param.lexeme, args[i])
-	}
+	enclosing := interpreter.class
+	defer func() { interpreter.class = enclosing }()
+	for {
+		interpreter.class = f.class
+		env, err := f.bindArguments(interpreter, args)
+		if err != nil {
+			return nil, err
+		}
+		if f.declaration.isGenerator {
+			return NewGenerator(interpreter, f, env), nil
+		}
+
+		val, err := interpreter.executeBlock(f.declaration.body, env)
+		if err != nil {
+			return nil, err
+		}
 
-	val, err := interpreter.executeBlock(f.declaration.body, env)
-	if err != nil {
 		if f.isInitializer {
 			return f.closure.GetAt(0, "this")
 		}
-		return nil, err
+
+		if tail, ok := val.value.(*tailCall); ok {
+			f, args = tail.function, tail.args
+			continue
+		}
+
+		return val.value, nil
+	}
+}
+
+// bindArguments creates the environment for a call, evaluating the defaults
+// of any parameters that weren't given an argument.
+func (f *Function) bindArguments(interpreter *Interpreter, args []interface{}) (*Environment, error) {
+	env := NewEnvironment(f.closure)
+	for i, param := range f.declaration.params {
+		if i < len(args) {
+			if _, ok := args[i].(missingArgument); !ok {
+				env.Define(param.lexeme, args[i])
+				continue
+			}
+		}
+		value, err := interpreter.evaluateIn(f.declaration.defaults[i], env)
+		if err != nil {
+			return nil, err
+		}
+		env.Define(param.lexeme, value)
+	}
+	if f.declaration.rest != nil {
+		extra := make([]interface{}, 0)
+		if len(args) > len(f.declaration.params) {
+			extra = append(extra, args[len(f.declaration.params):]...)
+		}
+		env.Define(f.declaration.rest.lexeme, NewList(extra))
+	}
+	return env, nil
+}
+
+// bindKeywords merges keyword arguments into the positional argument list,
+// leaving a missingArgument in any slot that should take its default.
+func (f *Function) bindKeywords(args []interface{}, names []string, values []interface{}) ([]interface{}, error) {
+	params := f.declaration.params
+	bound := make([]interface{}, len(params))
+	for i := range bound {
+		bound[i] = missingArgument{}
+	}
+	copy(bound, args)
+	if len(args) > len(params) {
+		bound = append(bound, args[len(params):]...)
 	}
 
-	if f.isInitializer {
-		return f.closure.GetAt(0, "this")
+	for k, name := range names {
+		index := -1
+		for i, param := range params {
+			if param.lexeme == name {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("%s got an unexpected keyword argument '%s'", f, name)
+		}
+		if _, ok := bound[index].(missingArgument); !ok {
+			return nil, fmt.Errorf("%s got multiple values for argument '%s'", f, name)
+		}
+		bound[index] = values[k]
 	}
 
-	return val.value, nil
+	for i, param := range params {
+		if _, ok := bound[i].(missingArgument); !ok {
+			continue
+		}
+		if f.declaration.defaults[i] == nil {
+			return nil, fmt.Errorf("%s missing argument '%s'", f, param.lexeme)
+		}
+	}
+	last := len(bound)
+	for last > 0 {
+		if _, ok := bound[last-1].(missingArgument); !ok {
+			break
+		}
+		last--
+	}
+	return bound[:last], nil
 }
 
 func (f *Function) String() string {
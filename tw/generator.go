@@ -0,0 +1,156 @@
+package tw
+
+import (
+	"fmt"
+	"runtime"
+)
+
+type generatorResult struct {
+	value interface{}
+	done  bool
+	err   error
+}
+
+// Generator is the value returned by calling a generator function. Its body
+// runs as a coroutine that is shut down by close(), or when the generator is
+// garbage collected, so abandoned generators don't leak goroutines.
+type Generator struct {
+	*coroutine
+}
+
+// coroutine runs the body of a generator function on its own goroutine,
+// handing control back and forth with the caller at every yield. Only one
+// side ever runs at a time. It never refers back to its Generator, so the
+// Generator can be collected while the goroutine is parked at a yield.
+type coroutine struct {
+	function *Function
+	env      *Environment
+	parent   *Interpreter
+	started  bool
+	running  bool
+	done     bool
+	buffered bool
+	value    interface{}
+	// resume wakes the goroutine at a yield: true to continue, false to
+	// unwind it because the generator was closed.
+	resume chan bool
+	yield  chan generatorResult
+}
+
+func NewGenerator(interpreter *Interpreter, function *Function, env *Environment) *Generator {
+	g := &Generator{&coroutine{
+		function: function,
+		env:      env,
+		parent:   interpreter,
+		resume:   make(chan bool),
+		yield:    make(chan generatorResult),
+	}}
+	runtime.SetFinalizer(g, func(g *Generator) {
+		g.close()
+	})
+	return g
+}
+
+func (g *Generator) Get(interpreter *Interpreter, name *Token) (interface{}, error) {
+	switch name.lexeme {
+	case "next":
+		return NewNativeFunction("next", 0, 0, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+			return g.Next()
+		}), nil
+	case "close":
+		return NewNativeFunction("close", 0, 0, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+			return nil, g.close()
+		}), nil
+	case "done":
+		if err := g.fill(); err != nil {
+			return nil, err
+		}
+		return g.done, nil
+	}
+	return nil, fmt.Errorf("undefined property '%s'", name.lexeme)
+}
+
+// Next returns the next yielded value, or nil once the generator is done.
+func (c *coroutine) Next() (interface{}, error) {
+	if err := c.fill(); err != nil {
+		return nil, err
+	}
+	if c.done {
+		return nil, nil
+	}
+	value := c.value
+	c.value = nil
+	c.buffered = false
+	return value, nil
+}
+
+// fill runs the generator up to its next yield unless a value is already
+// waiting, so that done can be answered before next is called.
+func (c *coroutine) fill() error {
+	if c.running {
+		return fmt.Errorf("generator %s is already running", c.function.declaration.name.lexeme)
+	}
+	if c.done || c.buffered {
+		return nil
+	}
+	c.running = true
+	if !c.started {
+		c.started = true
+		go c.run()
+	} else {
+		c.resume <- true
+	}
+	result := <-c.yield
+	c.running = false
+	if result.err != nil {
+		c.done = true
+		return result.err
+	}
+	if result.done {
+		c.done = true
+		return nil
+	}
+	c.value = result.value
+	c.buffered = true
+	return nil
+}
+
+// close finishes the generator, unwinding its goroutine if it is parked at a
+// yield.
+func (c *coroutine) close() error {
+	if c.running {
+		return fmt.Errorf("generator %s can't close itself while running", c.function.declaration.name.lexeme)
+	}
+	if c.done {
+		return nil
+	}
+	c.done = true
+	c.buffered = false
+	c.value = nil
+	if c.started {
+		c.resume <- false
+		<-c.yield
+	}
+	return nil
+}
+
+// run executes the body and reports how it finished, turning a Go panic into
+// an error for the consumer rather than crashing the process.
+func (c *coroutine) run() {
+	var err error
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("generator %s failed: %v", c.function.declaration.name.lexeme, r)
+		}
+		if err == errGeneratorClosed {
+			err = nil
+		}
+		c.yield <- generatorResult{done: true, err: err}
+	}()
+	interpreter := c.parent.fork(c)
+	_, err = interpreter.executeBlock(c.function.declaration.body, c.env)
+}
+
+func (g *Generator) String() string {
+	return fmt.Sprintf("<generator %s>", g.function.declaration.name.lexeme)
+}
@@ -1,34 +1,136 @@
 package tw
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
+// Instance keeps private members apart from its public fields, keyed by the
+// class that declared them, so a subclass can't see or clobber them.
 type Instance struct {
-	class  *Class
-	fields map[string]interface{}
+	class   *Class
+	fields  map[string]interface{}
+	private map[*Class]map[string]interface{}
+	// frozen is set once a record's initializer has run, after which its
+	// components can't be reassigned. Records hash by their components, so
+	// changing one would lose the record in any map it is a key of.
+	frozen bool
+	// printing is set while a record is being converted to a string, so a
+	// record that contains itself prints as Name(...) the second time.
+	printing bool
 }
 
 func NewInstance(class *Class) *Instance {
 	return &Instance{
-		class:  class,
-		fields: make(map[string]interface{}),
+		class:   class,
+		fields:  make(map[string]interface{}),
+		private: make(map[*Class]map[string]interface{}),
 	}
 }
 
-func (i *Instance) Get(name *Token) (interface{}, error) {
+func isPrivate(name string) bool {
+	return strings.HasPrefix(name, "#")
+}
+
+func (i *Instance) Get(interpreter *Interpreter, name *Token) (interface{}, error) {
+	if isPrivate(name.lexeme) {
+		return i.getPrivate(interpreter, name)
+	}
 	if value, ok := i.fields[name.lexeme]; ok {
 		return value, nil
 	}
 	method := i.class.FindMethod(name.lexeme)
 	if method != nil {
+		if method.declaration.isGetter {
+			return interpreter.invoke(name, i, method, nil)
+		}
 		return method.Bind(i), nil
 	}
 	return nil, fmt.Errorf("undefined property '%s'", name.lexeme)
 }
 
-func (i *Instance) Set(name *Token, value interface{}) {
+func (i *Instance) Set(interpreter *Interpreter, name *Token, value interface{}) error {
+	if isPrivate(name.lexeme) {
+		class, err := i.privateScope(interpreter, name)
+		if err != nil {
+			return err
+		}
+		i.define(class, name.lexeme, value)
+		return nil
+	}
+	if i.frozen && i.isComponent(name.lexeme) {
+		return fmt.Errorf("Cannot assign to component '%s' of record %s", name.lexeme, i.class.name)
+	}
+	if setter := i.class.FindSetter(name.lexeme); setter != nil {
+		_, err := interpreter.invoke(name, i, setter, []interface{}{value})
+		return err
+	}
 	i.fields[name.lexeme] = value
+	return nil
+}
+
+func (i *Instance) isComponent(name string) bool {
+	for _, component := range i.class.record {
+		if component == name {
+			return true
+		}
+	}
+	return false
+}
+
+// define stores a field declared by class without going through setters.
+func (i *Instance) define(class *Class, name string, value interface{}) {
+	if !isPrivate(name) {
+		i.fields[name] = value
+		return
+	}
+	if i.private[class] == nil {
+		i.private[class] = make(map[string]interface{})
+	}
+	i.private[class][name] = value
+}
+
+// getPrivate looks a private member up in the class of the running method
+// only, ignoring both subclasses and superclasses.
+func (i *Instance) getPrivate(interpreter *Interpreter, name *Token) (interface{}, error) {
+	class, err := i.privateScope(interpreter, name)
+	if err != nil {
+		return nil, err
+	}
+	if value, ok := i.private[class][name.lexeme]; ok {
+		return value, nil
+	}
+	if method, ok := class.methods[name.lexeme]; ok && method.class == class {
+		if method.declaration.isGetter {
+			return interpreter.invoke(name, i, method, nil)
+		}
+		return method.Bind(i), nil
+	}
+	return nil, fmt.Errorf("undefined property '%s'", name.lexeme)
+}
+
+// privateScope returns the class whose private members the running code may
+// access on this instance.
+func (i *Instance) privateScope(interpreter *Interpreter, name *Token) (*Class, error) {
+	class := interpreter.class
+	if class == nil || !i.class.inherits(class) {
+		return nil, fmt.Errorf("cannot access private member '%s' of %s", name.lexeme, i.class.name)
+	}
+	return class, nil
 }
 
 func (i *Instance) String() string {
+	if i.class.record != nil {
+		if i.printing {
+			return i.class.name + "(...)"
+		}
+		i.printing = true
+		defer func() { i.printing = false }()
+		components := make([]string, len(i.class.record))
+		for k, name := range i.class.record {
+			components[k] = name + ": " + stringify(i.fields[name])
+		}
+		return i.class.name + "(" + strings.Join(components, ", ") + ")"
+	}
 	return i.class.name + " instance"
 }
@@ -0,0 +1,19 @@
+package tw
+
+// Interface lists the methods a class must implement to declare that it
+// `implements` it.
+type Interface struct {
+	name    string
+	methods []*FunctionStmt
+}
+
+func NewInterface(name string, methods []*FunctionStmt) *Interface {
+	return &Interface{
+		name:    name,
+		methods: methods,
+	}
+}
+
+func (i *Interface) String() string {
+	return "<interface " + i.name + ">"
+}
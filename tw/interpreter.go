@@ -1,24 +1,65 @@
 package tw
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 )
 
+// DefaultMaxCallDepth bounds how deeply Lox calls may nest before a
+// "Stack overflow." runtime error is raised.
+const DefaultMaxCallDepth = 10000
+
 type Interpreter struct {
-	globals     *Environment
-	environment *Environment
-	locals      map[Expr]int
+	globals      *Environment
+	environment  *Environment
+	locals       map[Expr]int
+	imports      map[*ImportStmt]*Module
+	generator    *coroutine
+	class        *Class
+	depth        int
+	maxCallDepth int
 }
 
-func NewInterpreter() *Interpreter {
+// newGlobals creates a global environment holding the builtins. Each module
+// gets its own.
+func newGlobals() *Environment {
 	globals := NewGlobalEnvironment()
-
 	globals.Define("clock", &ClockBuiltin{})
+	defineReflection(globals)
+	return globals
+}
+
+func NewInterpreter() *Interpreter {
+	globals := newGlobals()
 
 	return &Interpreter{
-		globals:     globals,
-		environment: globals,
-		locals:      make(map[Expr]int),
+		globals:      globals,
+		environment:  globals,
+		locals:       make(map[Expr]int),
+		imports:      make(map[*ImportStmt]*Module),
+		maxCallDepth: DefaultMaxCallDepth,
+	}
+}
+
+// SetMaxCallDepth sets the call depth limit. A depth of zero or less removes
+// the limit entirely.
+func (i *Interpreter) SetMaxCallDepth(depth int) {
+	i.maxCallDepth = depth
+}
+
+// fork returns an interpreter sharing this one's globals and resolved locals,
+// used to run the body of generator g on its own goroutine.
+func (i *Interpreter) fork(g *coroutine) *Interpreter {
+	return &Interpreter{
+		globals:      i.globals,
+		environment:  i.globals,
+		locals:       i.locals,
+		imports:      i.imports,
+		generator:    g,
+		class:        g.function.class,
+		depth:        i.depth,
+		maxCallDepth: i.maxCallDepth,
 	}
 }
 
@@ -36,10 +77,23 @@ func (i *Interpreter) Resolve(expr Expr, depth int) {
 	i.locals[expr] = depth
 }
 
+// Import records the module loaded for an import statement.
+func (i *Interpreter) Import(stmt *ImportStmt, module *Module) {
+	i.imports[stmt] = module
+}
+
 func (i *Interpreter) evaluate(expr Expr) (interface{}, error) {
 	return expr.Accept(i)
 }
 
+// evaluateIn evaluates expr with env as the current environment.
+func (i *Interpreter) evaluateIn(expr Expr, env *Environment) (interface{}, error) {
+	prev := i.environment
+	i.environment = env
+	defer func() { i.environment = prev }()
+	return i.evaluate(expr)
+}
+
 func (i *Interpreter) execute(s Stmt) (StmtReturn, error) {
 	result, err := s.Accept(i)
 	if err != nil {
@@ -73,39 +127,185 @@ func (i *Interpreter) visitBlockStmt(stmt *BlockStmt) (StmtReturn, error) {
 }
 
 func (i *Interpreter) visitClassStmt(stmt *ClassStmt) (StmtReturn, error) {
-	var superclass interface{}
+	var superclass *Class
 	if stmt.superclass != nil {
 		val, err := i.evaluate(stmt.superclass)
 		if err != nil {
 			return StmtReturn{}, err
 		}
 
-		val, ok := val.(Class) // Type assertion
+		class, ok := val.(*Class)
 		if !ok {
-			i.error(stmt.superclass.name, "Superclass must be a class") // Ensure 'Name' exists
-			return StmtReturn{}, nil
+			return StmtReturn{}, i.error(stmt.superclass.name, "Superclass must be a class")
 		}
-		superclass = val
+		superclass = class
 	}
 
-	i.environment.Define(stmt.name.lexeme, nil)
-
-	if stmt.superclass != nil {
-		environment := NewEnvironment(i.environment)
-		environment.Define("super", superclass)
+	if err := i.define(stmt.name, nil); err != nil {
+		return StmtReturn{}, err
 	}
 
+	// Trait methods are copied into the class's own method table, so they
+	// override the superclass and are overridden by the class itself.
 	methods := make(map[string]*Function)
+	providers := make(map[string]*Trait)
+	for _, expr := range stmt.traits {
+		val, err := i.evaluate(expr)
+		if err != nil {
+			return StmtReturn{}, err
+		}
+		trait, ok := val.(*Trait)
+		if !ok {
+			return StmtReturn{}, i.error(expr.name, "Can only mix in traits")
+		}
+		for name, method := range trait.methods {
+			if other, ok := providers[name]; ok && !stmt.definesMethod(name) {
+				return StmtReturn{}, i.error(expr.name, fmt.Sprintf("Method '%s' is ambiguous between traits %s and %s", name, other.name, trait.name))
+			}
+			providers[name] = trait
+			methods[name] = method
+		}
+	}
+
+	if superclass != nil {
+		i.environment = NewEnvironment(i.environment)
+		i.environment.Define("super", superclass)
+	}
+
 	for _, method := range stmt.methods {
 		function := NewFunction(method, i.environment, method.name.lexeme == "init")
 		methods[method.name.lexeme] = function
 	}
 
-	class := NewClass(stmt.name.lexeme, superclass.(*Class), methods)
+	classMethods := make(map[string]*Function)
+	for _, method := range stmt.classMethods {
+		classMethods[method.name.lexeme] = NewFunction(method, i.environment, false)
+	}
+
+	setters := make(map[string]*Function)
+	for _, setter := range stmt.setters {
+		setters[setter.name.lexeme] = NewFunction(setter, i.environment, false)
+	}
+
+	metaclass := NewMetaclass(stmt.name.lexeme, superclass, classMethods)
+	class := NewClass(stmt.name.lexeme, superclass, metaclass, methods, setters)
+	class.declared = stmt.fields
+	for _, component := range stmt.record {
+		class.record = append(class.record, component.lexeme)
+	}
+	class.closure = i.environment
+	for _, method := range stmt.methods {
+		methods[method.name.lexeme].class = class
+	}
+	for _, function := range classMethods {
+		function.class = class
+	}
+	for _, function := range setters {
+		function.class = class
+	}
 	if superclass != nil {
 		i.environment = i.environment.enclosing
 	}
 	i.environment.Assign(stmt.name, class)
+
+	if err := i.checkAbstract(stmt, class); err != nil {
+		return StmtReturn{}, err
+	}
+
+	for _, field := range stmt.classFields {
+		var value interface{}
+		if field.initializer != nil {
+			v, err := i.evaluate(field.initializer)
+			if err != nil {
+				return StmtReturn{}, err
+			}
+			value = v
+		}
+		class.fields[field.name.lexeme] = value
+	}
+	return StmtReturn{}, nil
+}
+
+// runModule executes a module in its own globals the first time it is
+// imported.
+func (i *Interpreter) runModule(module *Module) error {
+	if module.globals != nil {
+		return nil
+	}
+	enclosing := i.class
+	i.class = nil
+	defer func() { i.class = enclosing }()
+
+	env := newGlobals()
+	if _, err := i.executeBlock(module.statements, env); err != nil {
+		return err
+	}
+	module.globals = env
+	return nil
+}
+
+// checkAbstract collects the methods a class is required to implement by its
+// superclass, its interfaces and its own abstract declarations. A class with
+// no abstract declarations of its own must implement all of them.
+func (i *Interpreter) checkAbstract(stmt *ClassStmt, class *Class) error {
+	type requirement struct {
+		signature *FunctionStmt
+		from      string
+	}
+	required := make([]requirement, 0)
+	if class.superclass != nil {
+		for _, signature := range class.superclass.abstract {
+			required = append(required, requirement{signature, class.superclass.name})
+		}
+	}
+	for _, expr := range stmt.interfaces {
+		val, err := i.evaluate(expr)
+		if err != nil {
+			return err
+		}
+		iface, ok := val.(*Interface)
+		if !ok {
+			return i.error(expr.name, "Can only implement interfaces")
+		}
+		for _, signature := range iface.methods {
+			required = append(required, requirement{signature, iface.name})
+		}
+	}
+	for _, signature := range stmt.abstracts {
+		required = append(required, requirement{signature, class.name})
+	}
+
+	for _, req := range required {
+		name := req.signature.name.lexeme
+		method := class.FindMethod(name)
+		if method == nil {
+			if len(stmt.abstracts) == 0 {
+				return i.error(stmt.name, fmt.Sprintf("Class %s must implement '%s' from %s", class.name, name, req.from))
+			}
+			class.abstract[name] = req.signature
+			continue
+		}
+		if !implements(method, req.signature) {
+			return i.error(stmt.name, fmt.Sprintf("Method '%s' of %s must accept %d arguments to implement %s", name, class.name, len(req.signature.params), req.from))
+		}
+	}
+	return nil
+}
+
+func (i *Interpreter) visitDestructureStmt(stmt *DestructureStmt) (StmtReturn, error) {
+	value, err := i.evaluate(stmt.initializer)
+	if err != nil {
+		return StmtReturn{}, err
+	}
+	values, err := i.destructure(stmt.pattern, value)
+	if err != nil {
+		return StmtReturn{}, err
+	}
+	for k, target := range stmt.pattern.targets {
+		if err := i.define(target.name, values[k]); err != nil {
+			return StmtReturn{}, err
+		}
+	}
 	return StmtReturn{}, nil
 }
 
@@ -117,9 +317,46 @@ func (i *Interpreter) visitExpressionStmt(stmt *ExpressionStmt) (StmtReturn, err
 	return StmtReturn{}, nil
 }
 
+func (i *Interpreter) visitForInStmt(stmt *ForInStmt) (StmtReturn, error) {
+	iterable, err := i.evaluate(stmt.iterable)
+	if err != nil {
+		return StmtReturn{}, err
+	}
+	iterator, err := newIterator(i, stmt.keyword, iterable)
+	if err != nil {
+		return StmtReturn{}, i.wrap(stmt.keyword, err)
+	}
+	for {
+		hasNext, err := iterator.HasNext()
+		if err != nil {
+			return StmtReturn{}, err
+		}
+		if !hasNext {
+			break
+		}
+		value, err := iterator.Next()
+		if err != nil {
+			return StmtReturn{}, err
+		}
+		env := NewEnvironment(i.environment)
+		env.Define(stmt.name.lexeme, value)
+		result, err := i.executeBlock([]Stmt{stmt.body}, env)
+		if err != nil {
+			return StmtReturn{}, err
+		}
+		if result.isReturn {
+			return result, nil
+		}
+	}
+	return StmtReturn{}, nil
+}
+
 func (i *Interpreter) visitFunctionStmt(stmt *FunctionStmt) (StmtReturn, error) {
 	function := NewFunction(stmt, i.environment, false)
-	i.environment.Define(stmt.name.lexeme, function)
+	function.class = i.class
+	if err := i.define(stmt.name, function); err != nil {
+		return StmtReturn{}, err
+	}
 	return StmtReturn{}, nil
 }
 
@@ -136,16 +373,73 @@ func (i *Interpreter) visitIfStmt(stmt *IfStmt) (StmtReturn, error) {
 	return StmtReturn{}, nil
 }
 
+func (i *Interpreter) visitEnumStmt(stmt *EnumStmt) (StmtReturn, error) {
+	enum := NewEnum(stmt.name.lexeme)
+	for k, member := range stmt.members {
+		var value interface{}
+		if stmt.values[k] != nil {
+			v, err := i.evaluate(stmt.values[k])
+			if err != nil {
+				return StmtReturn{}, err
+			}
+			value = v
+		}
+		enum.add(member.lexeme, value)
+	}
+	if err := i.define(stmt.name, enum); err != nil {
+		return StmtReturn{}, err
+	}
+	return StmtReturn{}, nil
+}
+
+func (i *Interpreter) visitImportStmt(stmt *ImportStmt) (StmtReturn, error) {
+	module := i.imports[stmt]
+	if err := i.runModule(module); err != nil {
+		return StmtReturn{}, err
+	}
+	if err := i.define(stmt.name, module); err != nil {
+		return StmtReturn{}, err
+	}
+	return StmtReturn{}, nil
+}
+
+func (i *Interpreter) visitExportStmt(stmt *ExportStmt) (StmtReturn, error) {
+	return i.execute(stmt.declaration)
+}
+
+func (i *Interpreter) visitInterfaceStmt(stmt *InterfaceStmt) (StmtReturn, error) {
+	if err := i.define(stmt.name, NewInterface(stmt.name.lexeme, stmt.methods)); err != nil {
+		return StmtReturn{}, err
+	}
+	return StmtReturn{}, nil
+}
+
 func (i *Interpreter) visitPrintStmt(stmt *PrintStmt) (StmtReturn, error) {
 	value, err := i.evaluate(stmt.expr)
 	if err != nil {
 		return StmtReturn{}, err
 	}
-	fmt.Println(value)
+	fmt.Println(stringify(value))
 	return StmtReturn{}, nil
 }
 
 func (i *Interpreter) visitReturnStmt(stmt *ReturnStmt) (StmtReturn, error) {
+	if call, ok := stmt.value.(*CallExpr); ok {
+		callee, args, err := i.evaluateCall(call)
+		if err != nil {
+			return StmtReturn{}, err
+		}
+		// Calls to Lox functions in tail position are handed back to the
+		// trampoline in Function.Call rather than growing the Go stack.
+		if function, ok := callee.(*Function); ok {
+			return StmtReturn{&tailCall{function, args}, true}, nil
+		}
+		value, err := i.call(call.paren, callee, args)
+		if err != nil {
+			return StmtReturn{}, err
+		}
+		return StmtReturn{value, true}, nil
+	}
 	var value interface{}
 	if stmt.value != nil {
 		v, err := i.evaluate(stmt.value)
@@ -157,6 +451,17 @@ func (i *Interpreter) visitReturnStmt(stmt *ReturnStmt) (StmtReturn, error) {
 	return StmtReturn{value, true}, nil
 }
 
+func (i *Interpreter) visitTraitStmt(stmt *TraitStmt) (StmtReturn, error) {
+	methods := make(map[string]*Function)
+	for _, method := range stmt.methods {
+		methods[method.name.lexeme] = NewFunction(method, i.environment, false)
+	}
+	if err := i.define(stmt.name, NewTrait(stmt.name.lexeme, methods)); err != nil {
+		return StmtReturn{}, err
+	}
+	return StmtReturn{}, nil
+}
+
 func (i *Interpreter) visitVarStmt(stmt *VarStmt) (StmtReturn, error) {
 	var value interface{}
 	if stmt.initializer != nil {
@@ -166,7 +471,31 @@ func (i *Interpreter) visitVarStmt(stmt *VarStmt) (StmtReturn, error) {
 		}
 		value = v
 	}
-	i.environment.Define(stmt.name.lexeme, value)
+	var err error
+	if stmt.isConst {
+		err = i.environment.DefineConstant(stmt.name.lexeme, value)
+	} else {
+		err = i.environment.Define(stmt.name.lexeme, value)
+	}
+	if err != nil {
+		return StmtReturn{}, i.error(stmt.name, err.Error())
+	}
+	return StmtReturn{}, nil
+}
+
+func (i *Interpreter) visitYieldStmt(stmt *YieldStmt) (StmtReturn, error) {
+	var value interface{}
+	if stmt.value != nil {
+		v, err := i.evaluate(stmt.value)
+		if err != nil {
+			return StmtReturn{}, err
+		}
+		value = v
+	}
+	i.generator.yield <- generatorResult{value: value}
+	if !<-i.generator.resume {
+		return StmtReturn{}, errGeneratorClosed
+	}
 	return StmtReturn{}, nil
 }
 
@@ -199,11 +528,8 @@ func (i *Interpreter) visitAssignExpr(expr *AssignExpr) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	distance, ok := i.locals[expr]
-	if ok {
-		i.environment.AssignAt(distance, expr.name, value)
-	} else {
-		i.globals.Assign(expr.name, value)
+	if err := i.assignVariable(expr.name, expr, value); err != nil {
+		return nil, err
 	}
 	return value, nil
 }
@@ -217,6 +543,21 @@ func (i *Interpreter) visitBinaryExpr(expr *BinaryExpr) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+	if name, ok := binaryOperators[expr.operator.ttype]; ok {
+		value, handled, err := i.operator(expr.operator, left, name, right)
+		if handled {
+			if err == nil && expr.operator.ttype == BANG_EQUAL {
+				return !isTruthy(value), nil
+			}
+			return value, err
+		}
+	}
+	switch expr.operator.ttype {
+	case GREATER, GREATER_EQUAL, LESS, LESS_EQUAL:
+		if a, b, ok := enumOrdinals(left, right); ok {
+			left, right = a, b
+		}
+	}
 	switch expr.operator.ttype {
 	case MINUS:
 		err := i.checkNumOperands(expr.operator, left, right)
@@ -271,34 +612,63 @@ func (i *Interpreter) visitBinaryExpr(expr *BinaryExpr) (interface{}, error) {
 			return nil, err
 		}
 		return left.(int) <= right.(int), nil
+	case IN:
+		contains, err := i.contains(expr.operator, right, left)
+		if err != nil {
+			return nil, i.wrap(expr.operator, err)
+		}
+		return contains, nil
+	case IS:
+		if enum, ok := right.(*Enum); ok {
+			member, ok := left.(*EnumMember)
+			return ok && member.enum == enum, nil
+		}
+		class, ok := right.(*Class)
+		if !ok {
+			return nil, i.error(expr.operator, "Right operand of 'is' must be a class")
+		}
+		instance, ok := left.(*Instance)
+		return ok && instance.class.inherits(class), nil
 	case BANG_EQUAL:
-		return !isEqual(left, right), nil
+		equal, err := i.isEqual(expr.operator, left, right)
+		return !equal, err
 	case EQUAL_EQUAL:
-		return isEqual(left, right), nil
+		return i.isEqual(expr.operator, left, right)
 	}
 	return nil, nil
 }
 
 func (i *Interpreter) visitCallExpr(expr *CallExpr) (interface{}, error) {
-	callee, err := i.evaluate(expr.callee)
+	callee, args, err := i.evaluateCall(expr)
 	if err != nil {
 		return nil, err
 	}
-	args := make([]interface{}, 0)
-	for _, arg := range expr.args {
-		value, err := i.evaluate(arg)
-		if err != nil {
-			return nil, err
-		}
-		args = append(args, value)
+	return i.call(expr.paren, callee, args)
+}
+
+func (i *Interpreter) visitClassOfExpr(expr *ClassOfExpr) (interface{}, error) {
+	object, err := i.evaluate(expr.object)
+	if err != nil {
+		return nil, err
 	}
-	if f, ok := callee.(Callable); ok {
-		if len(args) != f.Arity() {
-			return nil, i.error(expr.paren, fmt.Sprintf("Expected %d arguments but got %d", f.Arity(), len(args)))
+	return object.(*Instance).class, nil
+}
+
+func (i *Interpreter) visitDestructureExpr(expr *DestructureExpr) (interface{}, error) {
+	value, err := i.evaluate(expr.value)
+	if err != nil {
+		return nil, err
+	}
+	values, err := i.destructure(expr.pattern, value)
+	if err != nil {
+		return nil, err
+	}
+	for k, target := range expr.pattern.targets {
+		if err := i.assignVariable(target.name, target, values[k]); err != nil {
+			return nil, err
 		}
-		return f.Call(i, args)
 	}
-	return nil, i.error(expr.paren, "Can only call functions and classes")
+	return value, nil
 }
 
 func (i *Interpreter) visitGetExpr(expr *GetExpr) (interface{}, error) {
@@ -306,8 +676,15 @@ func (i *Interpreter) visitGetExpr(expr *GetExpr) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	if instance, ok := object.(*Instance); ok {
-		return instance.Get(expr.name)
+	if object == nil && expr.optional {
+		return nil, errShortCircuit
+	}
+	if object, ok := object.(Object); ok {
+		value, err := object.Get(i, expr.name)
+		if err != nil {
+			return nil, i.wrap(expr.name, err)
+		}
+		return value, nil
 	}
 	return nil, i.error(expr.name, "Only instances have properties")
 }
@@ -316,6 +693,104 @@ func (i *Interpreter) visitGroupingExpr(expr *GroupingExpr) (interface{}, error)
 	return i.evaluate(expr.expr)
 }
 
+func (i *Interpreter) visitIndexExpr(expr *IndexExpr) (interface{}, error) {
+	object, err := i.evaluate(expr.object)
+	if err != nil {
+		return nil, err
+	}
+	index, err := i.evaluate(expr.index)
+	if err != nil {
+		return nil, err
+	}
+	if value, handled, err := i.operator(expr.bracket, object, "__index__", index); handled {
+		return value, err
+	}
+	if rng, ok := index.(*Range); ok {
+		start, stop, step := rng.sliceBounds()
+		return i.slice(expr.bracket, object, start, stop, step)
+	}
+	var value interface{}
+	switch object := object.(type) {
+	case Indexable:
+		value, err = object.GetIndex(i, index)
+	case string:
+		value, err = indexString(object, index)
+	default:
+		return nil, i.error(expr.bracket, "Only strings, lists, tuples and maps can be indexed")
+	}
+	if err != nil {
+		return nil, i.wrap(expr.bracket, err)
+	}
+	return value, nil
+}
+
+func (i *Interpreter) visitIndexSetExpr(expr *IndexSetExpr) (interface{}, error) {
+	object, err := i.evaluate(expr.object)
+	if err != nil {
+		return nil, err
+	}
+	index, err := i.evaluate(expr.index)
+	if err != nil {
+		return nil, err
+	}
+	value, err := i.evaluate(expr.value)
+	if err != nil {
+		return nil, err
+	}
+	if _, handled, err := i.operator(expr.bracket, object, "__setindex__", index, value); handled {
+		return value, err
+	}
+	if object, ok := object.(Indexable); ok {
+		if err := object.SetIndex(i, index, value); err != nil {
+			return nil, i.wrap(expr.bracket, err)
+		}
+		return value, nil
+	}
+	return nil, i.error(expr.bracket, "Only lists and maps can be assigned by index")
+}
+
+func (i *Interpreter) visitListExpr(expr *ListExpr) (interface{}, error) {
+	elements, err := i.evaluateElements(expr.elements)
+	if err != nil {
+		return nil, err
+	}
+	return NewList(elements), nil
+}
+
+func (i *Interpreter) visitMapExpr(expr *MapExpr) (interface{}, error) {
+	m := NewMap()
+	for k, keyExpr := range expr.keys {
+		if spread, ok := keyExpr.(*SpreadExpr); ok {
+			value, err := i.evaluate(spread.expr)
+			if err != nil {
+				return nil, err
+			}
+			other, ok := value.(*Map)
+			if !ok {
+				return nil, i.error(spread.ellipsis, "Only maps can be spread into a map")
+			}
+			for _, entry := range other.entries {
+				if err := m.put(i, entry.hash, entry.key, entry.value); err != nil {
+					return nil, i.wrap(spread.ellipsis, err)
+				}
+			}
+			continue
+		}
+		key, err := i.evaluate(keyExpr)
+		if err != nil {
+			return nil, err
+		}
+		value, err := i.evaluate(expr.values[k])
+		if err != nil {
+			return nil, err
+		}
+		if err := m.SetIndex(i, key, value); err != nil {
+			return nil, i.wrap(expr.brace, err)
+		}
+	}
+	return m, nil
+}
+
 func (i *Interpreter) visitLiteralExpr(expr *LiteralExpr) (interface{}, error) {
 	return expr.value, nil
 }
@@ -325,7 +800,11 @@ func (i *Interpreter) visitLogicalExpr(expr *LogicalExpr) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	if expr.operator.ttype == OR {
+	if expr.operator.ttype == QUESTION_QUESTION {
+		if left != nil {
+			return left, nil
+		}
+	} else if expr.operator.ttype == OR {
 		if isTruthy(left) {
 			return left, nil
 		}
@@ -342,15 +821,69 @@ func (i *Interpreter) visitSetExpr(expr *SetExpr) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	if instance, ok := object.(*Instance); ok {
+	if object, ok := object.(Settable); ok {
 		value, err := i.evaluate(expr.value)
 		if err != nil {
 			return nil, err
 		}
-		instance.Set(expr.name, value)
+		if err := object.Set(i, expr.name, value); err != nil {
+			return nil, i.wrap(expr.name, err)
+		}
 		return value, nil
 	}
-	return nil, i.error(expr.name, "Only instances have fields")
+	return nil, i.error(expr.name, "Only instances and classes have fields")
+}
+
+func (i *Interpreter) visitSliceExpr(expr *SliceExpr) (interface{}, error) {
+	object, err := i.evaluate(expr.object)
+	if err != nil {
+		return nil, err
+	}
+	bounds := make([]interface{}, 3)
+	for k, bound := range []Expr{expr.start, expr.stop, expr.step} {
+		if bound == nil {
+			continue
+		}
+		bounds[k], err = i.evaluate(bound)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return i.slice(expr.bracket, object, bounds[0], bounds[1], bounds[2])
+}
+
+func (i *Interpreter) visitOptionalChainExpr(expr *OptionalChainExpr) (interface{}, error) {
+	value, err := i.evaluate(expr.expr)
+	if errors.Is(err, errShortCircuit) {
+		return nil, nil
+	}
+	return value, err
+}
+
+func (i *Interpreter) visitRangeExpr(expr *RangeExpr) (interface{}, error) {
+	bounds := []int{0, 0, 1}
+	for k, bound := range []Expr{expr.start, expr.stop, expr.step} {
+		if bound == nil {
+			continue
+		}
+		value, err := i.evaluate(bound)
+		if err != nil {
+			return nil, err
+		}
+		n, ok := value.(int)
+		if !ok {
+			return nil, i.error(expr.operator, "Range bounds and step must be numbers")
+		}
+		bounds[k] = n
+	}
+	if bounds[2] == 0 {
+		return nil, i.error(expr.operator, "Range step can't be zero")
+	}
+	return NewRange(bounds[0], bounds[1], bounds[2], expr.operator.ttype == DOT_DOT_EQUAL), nil
+}
+
+func (i *Interpreter) visitSpreadExpr(expr *SpreadExpr) (interface{}, error) {
+	return nil, i.error(expr.ellipsis, "Spread is only allowed in calls and list or map literals")
 }
 
 func (i *Interpreter) visitSuperExpr(expr *SuperExpr) (interface{}, error) {
@@ -365,13 +898,21 @@ func (i *Interpreter) visitSuperExpr(expr *SuperExpr) (interface{}, error) {
 		return nil, err
 	}
 
+	// Private members belong to the class that declared them, so a subclass
+	// can't reach its superclass's through super either.
+	if isPrivate(expr.method.lexeme) {
+		return nil, i.error(expr.method, fmt.Sprintf("Cannot access private member '%s' through 'super'", expr.method.lexeme))
+	}
+
 	method := superclass.(*Class).FindMethod(expr.method.lexeme)
 
 	if method == nil {
-		i.error(expr.method, "undefined property"+expr.method.lexeme+"'.")
-		return nil, nil
+		return nil, i.error(expr.method, "Undefined property '"+expr.method.lexeme+"'")
 	}
 
+	if method.declaration.isGetter {
+		return i.invoke(expr.method, object.(*Instance), method, nil)
+	}
 	return method.Bind(object.(*Instance)), nil
 }
 
@@ -379,11 +920,28 @@ func (i *Interpreter) visitThisExpr(expr *ThisExpr) (interface{}, error) {
 	return i.lookupVariable(expr.keyword, expr)
 }
 
+func (i *Interpreter) visitTupleExpr(expr *TupleExpr) (interface{}, error) {
+	elements := make([]interface{}, 0, len(expr.elements))
+	for _, element := range expr.elements {
+		value, err := i.evaluate(element)
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, value)
+	}
+	return NewTuple(elements), nil
+}
+
 func (i *Interpreter) visitUnaryExpr(expr *UnaryExpr) (interface{}, error) {
 	right, err := i.evaluate(expr.right)
 	if err != nil {
 		return nil, err
 	}
+	if expr.operator.ttype == MINUS {
+		if value, handled, err := i.operator(expr.operator, right, "__neg__"); handled {
+			return value, err
+		}
+	}
 	switch expr.operator.ttype {
 	case MINUS:
 		err := i.checkNumOperand(expr.operator, right)
@@ -405,12 +963,269 @@ func (i *Interpreter) visitVariableExpr(expr *VariableExpr) (interface{}, error)
 // ### HELPERS
 // ================================================================================
 
+// binaryOperators maps operators to the special methods that overload them.
+// != is the negation of __eq__.
+var binaryOperators = map[TokenType]string{
+	PLUS:          "__add__",
+	MINUS:         "__sub__",
+	STAR:          "__mul__",
+	SLASH:         "__div__",
+	LESS:          "__lt__",
+	LESS_EQUAL:    "__le__",
+	GREATER:       "__gt__",
+	GREATER_EQUAL: "__ge__",
+	EQUAL_EQUAL:   "__eq__",
+	BANG_EQUAL:    "__eq__",
+}
+
+// operator calls the special method name on an instance operand, reporting
+// whether the operand overloads it.
+func (i *Interpreter) operator(token *Token, operand interface{}, name string, args ...interface{}) (interface{}, bool, error) {
+	instance, ok := operand.(*Instance)
+	if !ok {
+		return nil, false, nil
+	}
+	method := instance.class.FindMethod(name)
+	if method == nil {
+		return nil, false, nil
+	}
+	bound := method.Bind(instance)
+	if err := i.checkArity(token, bound, len(args)); err != nil {
+		return nil, true, err
+	}
+	value, err := i.call(token, bound, args)
+	return value, true, err
+}
+
+// invoke calls a method of instance through call, so that methods run
+// implicitly by the interpreter are bound by the call depth limit too. Errors
+// are reported at token, the expression that caused the call, or at the
+// method's declaration when there is none.
+func (i *Interpreter) invoke(token *Token, instance *Instance, method *Function, args []interface{}) (interface{}, error) {
+	if token == nil {
+		token = method.declaration.name
+	}
+	bound := method.Bind(instance)
+	if err := i.checkArity(token, bound, len(args)); err != nil {
+		return nil, err
+	}
+	return i.call(token, bound, args)
+}
+
+// evaluateCall evaluates the callee and arguments of a call and checks them
+// against the callee's arity, without performing the call.
+func (i *Interpreter) evaluateCall(expr *CallExpr) (Callable, []interface{}, error) {
+	callee, err := i.evaluate(expr.callee)
+	if err != nil {
+		return nil, nil, err
+	}
+	if instance, ok := callee.(*Instance); ok {
+		if method := instance.class.FindMethod("__call__"); method != nil {
+			callee = method.Bind(instance)
+		}
+	}
+	args, err := i.evaluateElements(expr.args)
+	if err != nil {
+		return nil, nil, err
+	}
+	names := make([]string, 0, len(expr.kwargs))
+	values := make([]interface{}, 0, len(expr.kwargs))
+	for _, kwarg := range expr.kwargs {
+		value, err := i.evaluate(kwarg.value)
+		if err != nil {
+			return nil, nil, err
+		}
+		names = append(names, kwarg.name.lexeme)
+		values = append(values, value)
+	}
+	if f, ok := callee.(Callable); ok {
+		if len(names) > 0 {
+			var function *Function
+			switch c := callee.(type) {
+			case *Function:
+				function = c
+			case *Class:
+				function = c.FindMethod("init")
+			}
+			if function == nil {
+				return nil, nil, i.error(expr.paren, fmt.Sprintf("%v doesn't accept keyword arguments", callee))
+			}
+			args, err = function.bindKeywords(args, names, values)
+			if err != nil {
+				return nil, nil, i.wrap(expr.paren, err)
+			}
+		}
+		if err := i.checkArity(expr.paren, f, len(args)); err != nil {
+			return nil, nil, err
+		}
+		return f, args, nil
+	}
+	return nil, nil, i.error(expr.paren, "Can only call functions and classes")
+}
+
+func (i *Interpreter) call(paren *Token, callee Callable, args []interface{}) (interface{}, error) {
+	if i.maxCallDepth > 0 && i.depth >= i.maxCallDepth {
+		return nil, i.error(paren, "Stack overflow.")
+	}
+	i.depth++
+	defer func() { i.depth-- }()
+	if class, ok := callee.(*Class); ok && len(class.abstract) > 0 {
+		return nil, i.error(paren, fmt.Sprintf("Cannot instantiate abstract class '%s'", class.name))
+	}
+	value, err := callee.Call(i, args)
+	if _, ok := callee.(*NativeFunction); ok && err != nil {
+		return nil, i.wrap(paren, err)
+	}
+	return value, err
+}
+
+// evaluateElements evaluates a list of argument or element expressions,
+// expanding any SpreadExpr into the values of its iterable.
+func (i *Interpreter) evaluateElements(exprs []Expr) ([]interface{}, error) {
+	values := make([]interface{}, 0, len(exprs))
+	for _, expr := range exprs {
+		spread, ok := expr.(*SpreadExpr)
+		if !ok {
+			value, err := i.evaluate(expr)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+			continue
+		}
+		value, err := i.evaluate(spread.expr)
+		if err != nil {
+			return nil, err
+		}
+		iterator, err := newIterator(i, spread.ellipsis, value)
+		if err != nil {
+			return nil, i.wrap(spread.ellipsis, err)
+		}
+		for {
+			hasNext, err := iterator.HasNext()
+			if err != nil {
+				return nil, err
+			}
+			if !hasNext {
+				break
+			}
+			value, err := iterator.Next()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+func (i *Interpreter) slice(bracket *Token, object, start, stop, step interface{}) (interface{}, error) {
+	var value interface{}
+	var err error
+	switch object := object.(type) {
+	case Sliceable:
+		value, err = object.Slice(start, stop, step)
+	case string:
+		value, err = sliceString(object, start, stop, step)
+	default:
+		return nil, i.error(bracket, "Only strings, lists and tuples can be sliced")
+	}
+	if err != nil {
+		return nil, i.wrap(bracket, err)
+	}
+	return value, nil
+}
+
+// contains implements the `in` operator.
+func (i *Interpreter) contains(operator *Token, container, value interface{}) (bool, error) {
+	switch c := container.(type) {
+	case *Range:
+		return c.Contains(value), nil
+	case *List:
+		return i.containsEqual(operator, c.elements, value)
+	case *Tuple:
+		return i.containsEqual(operator, c.elements, value)
+	case *Map:
+		_, entry, err := c.lookup(i, value)
+		return entry != nil, err
+	case string:
+		s, ok := value.(string)
+		if !ok {
+			return false, fmt.Errorf("Left operand of 'in' must be a string when searching a string")
+		}
+		return strings.Contains(c, s), nil
+	}
+	return false, fmt.Errorf("Right operand of 'in' must be a range, string, list, tuple or map")
+}
+
 func (i *Interpreter) lookupVariable(name *Token, expr Expr) (interface{}, error) {
-	distance := i.locals[expr]
-	if distance != 0 {
+	distance, ok := i.locals[expr]
+	if ok {
 		return i.environment.GetAt(distance, name.lexeme)
 	} else {
-		return i.globals.Get(name)
+		return i.environment.global().Get(name)
+	}
+}
+
+func (i *Interpreter) assignVariable(name *Token, expr Expr, value interface{}) error {
+	var err error
+	if distance, ok := i.locals[expr]; ok {
+		err = i.environment.AssignAt(distance, name, value)
+	} else {
+		err = i.environment.global().Assign(name, value)
+	}
+	if err != nil {
+		return i.wrap(name, err)
+	}
+	return nil
+}
+
+// destructure unpacks value into one value per target of pattern.
+func (i *Interpreter) destructure(pattern *Pattern, value interface{}) ([]interface{}, error) {
+	values := make([]interface{}, len(pattern.targets))
+	if pattern.open.ttype == LEFT_BRACE {
+		object, ok := value.(*Instance)
+		if !ok {
+			return nil, i.error(pattern.open, "Only instances can be destructured by field")
+		}
+		for k, target := range pattern.targets {
+			field, err := object.Get(i, target.name)
+			if err != nil {
+				return nil, i.wrap(target.name, err)
+			}
+			values[k] = field
+		}
+		return values, nil
+	}
+
+	var elements []interface{}
+	switch v := value.(type) {
+	case *Tuple:
+		elements = v.elements
+	case *List:
+		elements = v.elements
+	default:
+		return nil, i.error(pattern.open, "Only tuples and lists can be destructured by position")
+	}
+	if len(elements) != len(pattern.targets) {
+		return nil, i.error(pattern.open, fmt.Sprintf("Expected %d values to unpack but got %d", len(pattern.targets), len(elements)))
+	}
+	copy(values, elements)
+	return values, nil
+}
+
+func (i *Interpreter) checkArity(paren *Token, callee Callable, count int) error {
+	min, max := callee.Arity()
+	if count >= min && (max == VariadicArity || count <= max) {
+		return nil
+	}
+	switch {
+	case max == VariadicArity:
+		return i.error(paren, fmt.Sprintf("Expected at least %d arguments but got %d", min, count))
+	case min == max:
+		return i.error(paren, fmt.Sprintf("Expected %d arguments but got %d", min, count))
+	default:
+		return i.error(paren, fmt.Sprintf("Expected %d to %d arguments but got %d", min, max, count))
 	}
 }
 
@@ -441,16 +1256,82 @@ func isTruthy(obj interface{}) bool {
 	return true
 }
 
-func isEqual(a, b interface{}) bool {
-	if a == nil && b == nil {
-		return true
+func stringify(value interface{}) string {
+	if value == nil {
+		return "nil"
 	}
-	if a == nil {
-		return false
+	return fmt.Sprintf("%v", value)
+}
+
+func (i *Interpreter) containsEqual(token *Token, elements []interface{}, value interface{}) (bool, error) {
+	for _, element := range elements {
+		equal, err := i.isEqual(token, element, value)
+		if err != nil || equal {
+			return equal, err
+		}
 	}
-	return a == b
+	return false, nil
+}
+
+// isEqual implements ==. Instances are compared by identity unless their
+// class defines __eq__, which is called at token. Records and tuples are
+// compared by their components.
+func (i *Interpreter) isEqual(token *Token, a, b interface{}) (bool, error) {
+	if a == b {
+		return true, nil
+	}
+	if x, ok := a.(*Tuple); ok {
+		y, ok := b.(*Tuple)
+		if !ok || len(x.elements) != len(y.elements) {
+			return false, nil
+		}
+		for k := range x.elements {
+			equal, err := i.isEqual(token, x.elements[k], y.elements[k])
+			if err != nil || !equal {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+	x, ok := a.(*Instance)
+	if !ok {
+		return a == b, nil
+	}
+	if method := x.class.FindMethod("__eq__"); method != nil {
+		value, err := i.invoke(token, x, method, []interface{}{b})
+		return isTruthy(value), err
+	}
+	y, ok := b.(*Instance)
+	if !ok || x.class != y.class || x.class.record == nil {
+		return false, nil
+	}
+	for _, component := range x.class.record {
+		equal, err := i.isEqual(token, x.fields[component], y.fields[component])
+		if err != nil || !equal {
+			return false, err
+		}
+	}
+	return true, nil
 }
 
 func (i *Interpreter) error(token *Token, message string) error {
-	return fmt.Errorf("[line %d] Error: %s", token.line, message)
+	return &RuntimeError{token: token, message: message}
+}
+
+// define binds a declared name in the current environment.
+func (i *Interpreter) define(name *Token, value interface{}) error {
+	if err := i.environment.Define(name.lexeme, value); err != nil {
+		return i.error(name, err.Error())
+	}
+	return nil
+}
+
+// wrap places an error from a value or native at token, unless it already
+// carries a line from the Lox code that raised it.
+func (i *Interpreter) wrap(token *Token, err error) error {
+	var runtimeErr *RuntimeError
+	if errors.As(err, &runtimeErr) || err == errShortCircuit || err == errGeneratorClosed {
+		return err
+	}
+	return i.error(token, err.Error())
 }
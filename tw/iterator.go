@@ -0,0 +1,150 @@
+package tw
+
+import "fmt"
+
+// Iterator produces the successive values of a for-in loop.
+type Iterator interface {
+	HasNext() (bool, error)
+	Next() (interface{}, error)
+}
+
+// Iterable is a built-in value that can be looped over with for-in.
+type Iterable interface {
+	Iterator() Iterator
+}
+
+type sliceIterator struct {
+	elements []interface{}
+	index    int
+}
+
+func (it *sliceIterator) HasNext() (bool, error) {
+	return it.index < len(it.elements), nil
+}
+
+func (it *sliceIterator) Next() (interface{}, error) {
+	value := it.elements[it.index]
+	it.index++
+	return value, nil
+}
+
+// listIterator reads the list as it goes, so elements pushed during the
+// loop are visited too.
+type listIterator struct {
+	list  *List
+	index int
+}
+
+func (it *listIterator) HasNext() (bool, error) {
+	return it.index < len(it.list.elements), nil
+}
+
+func (it *listIterator) Next() (interface{}, error) {
+	value := it.list.elements[it.index]
+	it.index++
+	return value, nil
+}
+
+// instanceIterator drives the hasNext() and next() methods of a Lox object
+// returned from a class's iterator() method. The calls go through the
+// interpreter so they count towards the call depth limit.
+type instanceIterator struct {
+	interpreter *Interpreter
+	token       *Token
+	hasNext     Callable
+	next        Callable
+}
+
+func (it *instanceIterator) HasNext() (bool, error) {
+	value, err := it.interpreter.call(it.token, it.hasNext, nil)
+	if err != nil {
+		return false, err
+	}
+	return isTruthy(value), nil
+}
+
+func (it *instanceIterator) Next() (interface{}, error) {
+	return it.interpreter.call(it.token, it.next, nil)
+}
+
+func (l *List) Iterator() Iterator {
+	return &listIterator{list: l}
+}
+
+func (t *Tuple) Iterator() Iterator {
+	return &sliceIterator{elements: t.elements}
+}
+
+func (m *Map) Iterator() Iterator {
+	keys := make([]interface{}, len(m.entries))
+	for i, entry := range m.entries {
+		keys[i] = entry.key
+	}
+	return &sliceIterator{elements: keys}
+}
+
+func (g *Generator) Iterator() Iterator {
+	return g
+}
+
+func (g *Generator) HasNext() (bool, error) {
+	if err := g.fill(); err != nil {
+		return false, err
+	}
+	return !g.done, nil
+}
+
+// newIterator returns an Iterator over a built-in iterable, a string, or an
+// instance whose class defines iterator(). Errors raised while iterating an
+// instance are reported at token.
+func newIterator(interpreter *Interpreter, token *Token, value interface{}) (Iterator, error) {
+	switch v := value.(type) {
+	case Iterable:
+		return v.Iterator(), nil
+	case string:
+		runes := []rune(v)
+		elements := make([]interface{}, len(runes))
+		for i, r := range runes {
+			elements[i] = string(r)
+		}
+		return &sliceIterator{elements: elements}, nil
+	case *Instance:
+		method := v.class.FindMethod("iterator")
+		if method == nil {
+			return nil, fmt.Errorf("%s has no iterator() method.", v)
+		}
+		iterator, err := interpreter.invoke(token, v, method, nil)
+		if err != nil {
+			return nil, err
+		}
+		if iterable, ok := iterator.(Iterable); ok {
+			return iterable.Iterator(), nil
+		}
+		object, ok := iterator.(Object)
+		if !ok {
+			return nil, fmt.Errorf("iterator() must return an object with hasNext() and next().")
+		}
+		hasNext, err := callableProperty(interpreter, object, "hasNext")
+		if err != nil {
+			return nil, err
+		}
+		next, err := callableProperty(interpreter, object, "next")
+		if err != nil {
+			return nil, err
+		}
+		return &instanceIterator{interpreter: interpreter, token: token, hasNext: hasNext, next: next}, nil
+	}
+	return nil, fmt.Errorf("Can only iterate over strings, lists, tuples, maps, generators and iterable instances.")
+}
+
+func callableProperty(interpreter *Interpreter, object Object, name string) (Callable, error) {
+	value, err := object.Get(interpreter, &Token{ttype: IDENTIFIER, lexeme: name})
+	if err != nil {
+		return nil, fmt.Errorf("Iterator must define %s().", name)
+	}
+	callable, ok := value.(Callable)
+	if !ok {
+		return nil, fmt.Errorf("Iterator must define %s().", name)
+	}
+	return callable, nil
+}
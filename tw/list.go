@@ -0,0 +1,131 @@
+package tw
+
+import (
+	"fmt"
+	"strings"
+)
+
+type List struct {
+	elements []interface{}
+	// printing is set while the list is being converted to a string, so a
+	// list that contains itself prints as [...] the second time.
+	printing bool
+}
+
+func NewList(elements []interface{}) *List {
+	return &List{
+		elements: elements,
+	}
+}
+
+func (l *List) Get(interpreter *Interpreter, name *Token) (interface{}, error) {
+	switch name.lexeme {
+	case "push":
+		return NewNativeFunction("push", 1, 1, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+			l.elements = append(l.elements, args[0])
+			return nil, nil
+		}), nil
+	case "pop":
+		return NewNativeFunction("pop", 0, 0, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+			if len(l.elements) == 0 {
+				return nil, fmt.Errorf("Can't pop from an empty list.")
+			}
+			value := l.elements[len(l.elements)-1]
+			l.elements = l.elements[:len(l.elements)-1]
+			return value, nil
+		}), nil
+	case "insert":
+		return NewNativeFunction("insert", 2, 2, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+			index, ok := args[0].(int)
+			if !ok {
+				return nil, fmt.Errorf("List index must be a number.")
+			}
+			if index < 0 {
+				index += len(l.elements) + 1
+			}
+			if index < 0 || index > len(l.elements) {
+				return nil, fmt.Errorf("List index out of range.")
+			}
+			l.elements = append(l.elements, nil)
+			copy(l.elements[index+1:], l.elements[index:])
+			l.elements[index] = args[1]
+			return nil, nil
+		}), nil
+	case "remove":
+		return NewNativeFunction("remove", 1, 1, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+			index, err := l.index(args[0])
+			if err != nil {
+				return nil, err
+			}
+			value := l.elements[index]
+			l.elements = append(l.elements[:index], l.elements[index+1:]...)
+			return value, nil
+		}), nil
+	case "len":
+		return NewNativeFunction("len", 0, 0, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+			return len(l.elements), nil
+		}), nil
+	case "contains":
+		return NewNativeFunction("contains", 1, 1, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+			return interpreter.containsEqual(nil, l.elements, args[0])
+		}), nil
+	case "reverse":
+		return NewNativeFunction("reverse", 0, 0, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+			for i, j := 0, len(l.elements)-1; i < j; i, j = i+1, j-1 {
+				l.elements[i], l.elements[j] = l.elements[j], l.elements[i]
+			}
+			return nil, nil
+		}), nil
+	}
+	return nil, fmt.Errorf("undefined property '%s'", name.lexeme)
+}
+
+func (l *List) GetIndex(interpreter *Interpreter, index interface{}) (interface{}, error) {
+	i, err := l.index(index)
+	if err != nil {
+		return nil, err
+	}
+	return l.elements[i], nil
+}
+
+func (l *List) SetIndex(interpreter *Interpreter, index interface{}, value interface{}) error {
+	i, err := l.index(index)
+	if err != nil {
+		return err
+	}
+	l.elements[i] = value
+	return nil
+}
+
+func (l *List) index(index interface{}) (int, error) {
+	return sequenceIndex("List", index, len(l.elements))
+}
+
+func (l *List) String() string {
+	if l.printing {
+		return "[...]"
+	}
+	l.printing = true
+	defer func() { l.printing = false }()
+	parts := make([]string, len(l.elements))
+	for i, element := range l.elements {
+		parts[i] = stringify(element)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// sequenceIndex converts a Lox index into a position in a sequence of the
+// given length, counting negative indices back from the end.
+func sequenceIndex(kind string, index interface{}, length int) (int, error) {
+	i, ok := index.(int)
+	if !ok {
+		return 0, fmt.Errorf("%s index must be a number.", kind)
+	}
+	if i < 0 {
+		i += length
+	}
+	if i < 0 || i >= length {
+		return 0, fmt.Errorf("%s index out of range.", kind)
+	}
+	return i, nil
+}
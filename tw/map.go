@@ -0,0 +1,237 @@
+package tw
+
+import (
+	"fmt"
+	"strings"
+)
+
+type mapEntry struct {
+	hash  interface{}
+	key   interface{}
+	value interface{}
+}
+
+// Map stores its entries in insertion order, indexed by the canonical key
+// returned from hashKey. Keys with the same hash share a bucket and are told
+// apart with ==.
+type Map struct {
+	index   map[interface{}][]*mapEntry
+	entries []*mapEntry
+	// printing is set while the map is being converted to a string, so a
+	// map that contains itself prints as {...} the second time.
+	printing bool
+}
+
+func NewMap() *Map {
+	return &Map{
+		index:   make(map[interface{}][]*mapEntry),
+		entries: make([]*mapEntry, 0),
+	}
+}
+
+// instanceHash is the canonical key of an instance whose class defines
+// hash(). Instances of the same class with equal hashes share a bucket.
+type instanceHash struct {
+	class *Class
+	hash  interface{}
+}
+
+// tupleHash is the canonical key of a tuple, built from its elements.
+type tupleHash struct {
+	elements interface{}
+}
+
+// hashPair chains the hashes of a sequence of values into a single
+// comparable value.
+type hashPair struct {
+	rest interface{}
+	last interface{}
+}
+
+func hashElements(interpreter *Interpreter, values []interface{}) (interface{}, error) {
+	var hash interface{}
+	for _, value := range values {
+		element, err := hashKey(interpreter, value)
+		if err != nil {
+			return nil, err
+		}
+		hash = hashPair{hash, element}
+	}
+	return hash, nil
+}
+
+// recordHash keys a record by its class and the hashes of its components, so
+// records that are == share a bucket.
+func recordHash(interpreter *Interpreter, record *Instance) (interface{}, error) {
+	components := make([]interface{}, len(record.class.record))
+	for k, name := range record.class.record {
+		components[k] = record.fields[name]
+	}
+	hash, err := hashElements(interpreter, components)
+	if err != nil {
+		return nil, err
+	}
+	return instanceHash{record.class, hash}, nil
+}
+
+// hashKey returns the comparable Go value that identifies key within a Map.
+// Primitives hash by value, instances by identity unless their class defines
+// hash(), tuples by their elements, and functions and classes by identity.
+// hash() is only called here, when an instance is used as a key.
+// Lists and maps are mutable and can't be used as keys.
+func hashKey(interpreter *Interpreter, key interface{}) (interface{}, error) {
+	switch k := key.(type) {
+	case nil, bool, int, string:
+		return k, nil
+	case *Instance:
+		if k.class.record != nil {
+			return recordHash(interpreter, k)
+		}
+		method := k.class.FindMethod("hash")
+		if method == nil {
+			return k, nil
+		}
+		hash, err := interpreter.invoke(nil, k, method, nil)
+		if err != nil {
+			return nil, err
+		}
+		switch hash.(type) {
+		case nil, bool, int, string:
+			return instanceHash{k.class, hash}, nil
+		}
+		return nil, fmt.Errorf("hash() must return a number, string, boolean or nil.")
+	case *Tuple:
+		hash, err := hashElements(interpreter, k.elements)
+		if err != nil {
+			return nil, err
+		}
+		return tupleHash{hash}, nil
+	case *List, *Map:
+		return nil, fmt.Errorf("Unhashable map key: %s.", stringify(key))
+	}
+	return key, nil
+}
+
+// lookup returns the hash of key and its entry, which is nil if the map
+// doesn't contain key.
+func (m *Map) lookup(interpreter *Interpreter, key interface{}) (interface{}, *mapEntry, error) {
+	hash, err := hashKey(interpreter, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	entry, err := m.find(interpreter, hash, key)
+	return hash, entry, err
+}
+
+func (m *Map) find(interpreter *Interpreter, hash, key interface{}) (*mapEntry, error) {
+	for _, entry := range m.index[hash] {
+		equal, err := interpreter.isEqual(nil, entry.key, key)
+		if err != nil {
+			return nil, err
+		}
+		if equal {
+			return entry, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *Map) GetIndex(interpreter *Interpreter, key interface{}) (interface{}, error) {
+	_, entry, err := m.lookup(interpreter, key)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("Undefined map key: %s.", stringify(key))
+	}
+	return entry.value, nil
+}
+
+func (m *Map) SetIndex(interpreter *Interpreter, key interface{}, value interface{}) error {
+	hash, err := hashKey(interpreter, key)
+	if err != nil {
+		return err
+	}
+	return m.put(interpreter, hash, key, value)
+}
+
+func (m *Map) put(interpreter *Interpreter, hash, key, value interface{}) error {
+	entry, err := m.find(interpreter, hash, key)
+	if err != nil {
+		return err
+	}
+	if entry != nil {
+		entry.value = value
+		return nil
+	}
+	entry = &mapEntry{hash: hash, key: key, value: value}
+	m.index[hash] = append(m.index[hash], entry)
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+func (m *Map) Get(interpreter *Interpreter, name *Token) (interface{}, error) {
+	switch name.lexeme {
+	case "keys":
+		return NewNativeFunction("keys", 0, 0, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+			keys := make([]interface{}, len(m.entries))
+			for i, entry := range m.entries {
+				keys[i] = entry.key
+			}
+			return NewList(keys), nil
+		}), nil
+	case "values":
+		return NewNativeFunction("values", 0, 0, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+			values := make([]interface{}, len(m.entries))
+			for i, entry := range m.entries {
+				values[i] = entry.value
+			}
+			return NewList(values), nil
+		}), nil
+	case "has":
+		return NewNativeFunction("has", 1, 1, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+			_, entry, err := m.lookup(interpreter, args[0])
+			return entry != nil, err
+		}), nil
+	case "delete":
+		return NewNativeFunction("delete", 1, 1, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+			hash, entry, err := m.lookup(interpreter, args[0])
+			if err != nil || entry == nil {
+				return false, err
+			}
+			m.index[hash] = removeEntry(m.index[hash], entry)
+			if len(m.index[hash]) == 0 {
+				delete(m.index, hash)
+			}
+			m.entries = removeEntry(m.entries, entry)
+			return true, nil
+		}), nil
+	case "len":
+		return NewNativeFunction("len", 0, 0, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+			return len(m.entries), nil
+		}), nil
+	}
+	return nil, fmt.Errorf("undefined property '%s'", name.lexeme)
+}
+
+func removeEntry(entries []*mapEntry, entry *mapEntry) []*mapEntry {
+	for i, e := range entries {
+		if e == entry {
+			return append(entries[:i], entries[i+1:]...)
+		}
+	}
+	return entries
+}
+
+func (m *Map) String() string {
+	if m.printing {
+		return "{...}"
+	}
+	m.printing = true
+	defer func() { m.printing = false }()
+	parts := make([]string, len(m.entries))
+	for i, entry := range m.entries {
+		parts[i] = stringify(entry.key) + ": " + stringify(entry.value)
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
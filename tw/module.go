@@ -0,0 +1,46 @@
+package tw
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Module is a loaded source file. It runs the first time it is imported,
+// and its exports are then read from its globals, so importers see any later
+// assignments to them.
+type Module struct {
+	path       string
+	statements []Stmt
+	exports    map[string]bool
+	globals    *Environment
+}
+
+func NewModule(path string, statements []Stmt) *Module {
+	exports := make(map[string]bool)
+	for _, stmt := range statements {
+		if export, ok := stmt.(*ExportStmt); ok {
+			exports[export.name.lexeme] = true
+		}
+	}
+	return &Module{
+		path:       path,
+		statements: statements,
+		exports:    exports,
+	}
+}
+
+func (m *Module) Get(interpreter *Interpreter, name *Token) (interface{}, error) {
+	if m.exports[name.lexeme] {
+		return m.globals.Get(name)
+	}
+	return nil, fmt.Errorf("module '%s' has no export '%s'", m.name(), name.lexeme)
+}
+
+func (m *Module) name() string {
+	return strings.TrimSuffix(filepath.Base(m.path), filepath.Ext(m.path))
+}
+
+func (m *Module) String() string {
+	return "<module " + m.name() + ">"
+}
@@ -0,0 +1,18 @@
+package tw
+
+// Object is a runtime value whose properties can be read with a GetExpr.
+type Object interface {
+	Get(interpreter *Interpreter, name *Token) (interface{}, error)
+}
+
+// Settable is an Object whose properties can also be written with a SetExpr.
+type Settable interface {
+	Object
+	Set(interpreter *Interpreter, name *Token, value interface{}) error
+}
+
+// Indexable is a runtime value that supports `value[index]` reads and writes.
+type Indexable interface {
+	GetIndex(interpreter *Interpreter, index interface{}) (interface{}, error)
+	SetIndex(interpreter *Interpreter, index interface{}, value interface{}) error
+}
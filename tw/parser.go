@@ -8,6 +8,7 @@ type Parser struct {
 	tokens  []*Token
 	current int
 	hadErr  bool
+	yields  bool
 }
 
 func NewParser(tokens []*Token) *Parser {
@@ -44,6 +45,12 @@ func (p *Parser) statement() Stmt {
 	if p.match(WHILE) {
 		return p.whileStatement()
 	}
+	if p.match(YIELD) {
+		return p.yieldStatement()
+	}
+	if p.check(LEFT_BRACE) && p.patternAhead() {
+		return p.expressionStatement()
+	}
 	if p.match(LEFT_BRACE) {
 		return &BlockStmt{stmts: p.block()}
 	}
@@ -51,7 +58,11 @@ func (p *Parser) statement() Stmt {
 }
 
 func (p *Parser) forStatement() Stmt {
+	keyword := p.previous()
 	p.consume(LEFT_PAREN, "Expect '(' after 'for'.")
+	if p.check(VAR) && p.checkNext(IDENTIFIER) && p.tokens[p.current+2].ttype == IN {
+		return p.forInStatement(keyword)
+	}
 	var initializer Stmt
 	if p.match(SEMICOLON) {
 		initializer = nil
@@ -84,6 +95,16 @@ func (p *Parser) forStatement() Stmt {
 	return body
 }
 
+func (p *Parser) forInStatement(keyword *Token) Stmt {
+	p.advance()
+	name := p.advance()
+	p.advance()
+	iterable := p.expression()
+	p.consume(RIGHT_PAREN, "Expect ')' after for-in clause.")
+	body := p.statement()
+	return &ForInStmt{keyword: keyword, name: name, iterable: iterable, body: body}
+}
+
 func (p *Parser) ifStatement() Stmt {
 	p.consume(LEFT_PAREN, "Expect '(' after 'if'.")
 	condition := p.expression()
@@ -107,6 +128,13 @@ func (p *Parser) returnStatement() Stmt {
 	var value Expr
 	if !p.check(SEMICOLON) {
 		value = p.expression()
+		if p.match(COMMA) {
+			elements := []Expr{value, p.expression()}
+			for p.match(COMMA) {
+				elements = append(elements, p.expression())
+			}
+			value = &TupleExpr{paren: keyword, elements: elements}
+		}
 	}
 	p.consume(SEMICOLON, "Expect ';' after return value.")
 	return &ReturnStmt{keyword: keyword, value: value}
@@ -120,6 +148,17 @@ func (p *Parser) whileStatement() Stmt {
 	return &WhileStmt{condition: condition, body: body}
 }
 
+func (p *Parser) yieldStatement() Stmt {
+	keyword := p.previous()
+	var value Expr
+	if !p.check(SEMICOLON) {
+		value = p.expression()
+	}
+	p.consume(SEMICOLON, "Expect ';' after yield value.")
+	p.yields = true
+	return &YieldStmt{keyword: keyword, value: value}
+}
+
 func (p *Parser) expressionStatement() Stmt {
 	expr := p.expression()
 	p.consume(SEMICOLON, "Expect ';' after expression.")
@@ -140,19 +179,84 @@ func (p *Parser) declaration() Stmt {
 		p.synchronize()
 		return nil
 	}
+	if p.match(IMPORT) {
+		return p.importDeclaration()
+	}
+	if p.match(EXPORT) {
+		return p.exportDeclaration()
+	}
 	if p.match(CLASS) {
 		return p.classDeclaration()
 	}
+	if p.match(TRAIT) {
+		return p.traitDeclaration()
+	}
+	if p.match(INTERFACE) {
+		return p.interfaceDeclaration()
+	}
+	if p.match(ENUM) {
+		return p.enumDeclaration()
+	}
+	if p.match(RECORD) {
+		return p.recordDeclaration()
+	}
 	if p.match(FUN) {
 		return p.function("function")
 	}
 	if p.match(VAR) {
 		return p.varDeclaration()
 	}
+	if p.match(CONST) {
+		return p.constDeclaration()
+	}
 	return p.statement()
 }
 
+func (p *Parser) importDeclaration() Stmt {
+	keyword := p.previous()
+	path := p.consume(STRING, "Expect module path after 'import'.")
+	if !p.check(IDENTIFIER) || p.peek().lexeme != "as" {
+		p.error(p.peek(), "Expect 'as' after module path.")
+		return nil
+	}
+	p.advance()
+	name := p.consume(IDENTIFIER, "Expect module name after 'as'.")
+	p.consume(SEMICOLON, "Expect ';' after import.")
+	return &ImportStmt{keyword: keyword, path: path, name: name}
+}
+
+func (p *Parser) exportDeclaration() Stmt {
+	keyword := p.previous()
+	declaration := p.declaration()
+	var name *Token
+	switch stmt := declaration.(type) {
+	case *FunctionStmt:
+		name = stmt.name
+	case *ClassStmt:
+		name = stmt.name
+	case *TraitStmt:
+		name = stmt.name
+	case *InterfaceStmt:
+		name = stmt.name
+	case *EnumStmt:
+		name = stmt.name
+	case *VarStmt:
+		name = stmt.name
+	default:
+		p.error(keyword, "Can only export named declarations.")
+		return nil
+	}
+	return &ExportStmt{keyword: keyword, name: name, declaration: declaration}
+}
+
 func (p *Parser) varDeclaration() Stmt {
+	if p.check(LEFT_PAREN) || p.check(LEFT_BRACKET) || p.check(LEFT_BRACE) {
+		pattern := p.pattern()
+		p.consume(EQUAL, "Expect '=' after destructuring pattern.")
+		initializer := p.expression()
+		p.consume(SEMICOLON, "Expect ';' after variable declaration.")
+		return &DestructureStmt{pattern: pattern, initializer: initializer}
+	}
 	name := p.consume(IDENTIFIER, "Expect variable name.")
 	var initializer Expr
 	if p.match(EQUAL) {
@@ -162,6 +266,14 @@ func (p *Parser) varDeclaration() Stmt {
 	return &VarStmt{name: name, initializer: initializer}
 }
 
+func (p *Parser) constDeclaration() Stmt {
+	name := p.consume(IDENTIFIER, "Expect constant name.")
+	p.consume(EQUAL, "Expect '=' after constant name.")
+	initializer := p.expression()
+	p.consume(SEMICOLON, "Expect ';' after constant declaration.")
+	return &VarStmt{name: name, initializer: initializer, isConst: true}
+}
+
 func (p *Parser) classDeclaration() Stmt {
 	name := p.consume(IDENTIFIER, "Expect class name.")
 	var superclass *VariableExpr
@@ -169,37 +281,261 @@ func (p *Parser) classDeclaration() Stmt {
 		p.consume(IDENTIFIER, "Expect superclass name")
 		superclass = &VariableExpr{name: p.previous()}
 	}
+	traits := make([]*VariableExpr, 0)
+	if p.match(WITH) {
+		for {
+			p.consume(IDENTIFIER, "Expect trait name.")
+			traits = append(traits, &VariableExpr{name: p.previous()})
+			if !p.match(COMMA) {
+				break
+			}
+		}
+	}
+	interfaces := make([]*VariableExpr, 0)
+	if p.match(IMPLEMENTS) {
+		for {
+			p.consume(IDENTIFIER, "Expect interface name.")
+			interfaces = append(interfaces, &VariableExpr{name: p.previous()})
+			if !p.match(COMMA) {
+				break
+			}
+		}
+	}
 
 	p.consume(LEFT_BRACE, "Expect '{' before class body.")
 	methods := make([]*FunctionStmt, 0)
+	setters := make([]*FunctionStmt, 0)
+	abstracts := make([]*FunctionStmt, 0)
+	classMethods := make([]*FunctionStmt, 0)
+	classFields := make([]*VarStmt, 0)
+	fields := make([]*VarStmt, 0)
 	for !p.check(RIGHT_BRACE) && !p.isAtEnd() {
+		if p.match(CLASS) {
+			if p.checkNext(LEFT_PAREN) {
+				classMethods = append(classMethods, p.function("method").(*FunctionStmt))
+			} else {
+				classFields = append(classFields, p.field())
+			}
+			continue
+		}
+		if p.check(IDENTIFIER) && (p.checkNext(EQUAL) || p.checkNext(SEMICOLON)) {
+			fields = append(fields, p.field())
+			continue
+		}
+		if p.match(ABSTRACT) {
+			abstracts = append(abstracts, p.signature())
+			continue
+		}
+		if p.check(IDENTIFIER) && p.peek().lexeme == "set" && p.checkNext(IDENTIFIER) {
+			p.advance()
+			setter := p.function("setter").(*FunctionStmt)
+			if len(setter.params) != 1 || setter.rest != nil {
+				p.error(setter.name, "A setter must take exactly one parameter.")
+			}
+			setters = append(setters, setter)
+			continue
+		}
 		methods = append(methods, p.function("method").(*FunctionStmt))
 	}
 	p.consume(RIGHT_BRACE, "Expect '}' after class body.")
-	return &ClassStmt{name: name, superclass: superclass, methods: methods}
+	return &ClassStmt{name: name, superclass: superclass, traits: traits, interfaces: interfaces, methods: methods, abstracts: abstracts, setters: setters, classMethods: classMethods, classFields: classFields, fields: fields}
+}
+
+func (p *Parser) traitDeclaration() Stmt {
+	name := p.consume(IDENTIFIER, "Expect trait name.")
+	p.consume(LEFT_BRACE, "Expect '{' before trait body.")
+	methods := make([]*FunctionStmt, 0)
+	for !p.check(RIGHT_BRACE) && !p.isAtEnd() {
+		methods = append(methods, p.function("method").(*FunctionStmt))
+	}
+	p.consume(RIGHT_BRACE, "Expect '}' after trait body.")
+	return &TraitStmt{name: name, methods: methods}
+}
+
+// recordDeclaration parses `record Name(a, b);`, optionally followed by a
+// body of methods instead of the semicolon. The initializer and the with()
+// copy method are generated as ordinary methods.
+func (p *Parser) recordDeclaration() Stmt {
+	name := p.consume(IDENTIFIER, "Expect record name.")
+	if name == nil {
+		return nil
+	}
+	p.consume(LEFT_PAREN, "Expect '(' after record name.")
+	components := make([]*Token, 0)
+	if !p.check(RIGHT_PAREN) {
+		for {
+			components = append(components, p.consume(IDENTIFIER, "Expect component name."))
+			if !p.match(COMMA) {
+				break
+			}
+		}
+	}
+	p.consume(RIGHT_PAREN, "Expect ')' after record components.")
+
+	methods := []*FunctionStmt{recordInit(name, components), recordWith(name, components)}
+	if p.match(LEFT_BRACE) {
+		for !p.check(RIGHT_BRACE) && !p.isAtEnd() {
+			methods = append(methods, p.function("method").(*FunctionStmt))
+		}
+		p.consume(RIGHT_BRACE, "Expect '}' after record body.")
+	} else {
+		p.consume(SEMICOLON, "Expect ';' after record declaration.")
+	}
+	return &ClassStmt{name: name, methods: methods, record: components}
+}
+
+// generatedToken returns a token for code the parser writes itself, placed
+// at the token it was generated for.
+func generatedToken(ttype TokenType, lexeme string, at *Token) *Token {
+	token := NewToken(ttype, lexeme, nil, at.line)
+	token.file = at.file
+	return token
+}
+
+// recordInit builds `init(a, b) { this.a = a; this.b = b; }`.
+func recordInit(name *Token, components []*Token) *FunctionStmt {
+	defaults := make([]Expr, len(components))
+	body := make([]Stmt, 0, len(components))
+	for _, component := range components {
+		this := &ThisExpr{keyword: generatedToken(THIS, "this", name)}
+		body = append(body, &ExpressionStmt{expr: &SetExpr{object: this, name: component, value: &VariableExpr{name: component}}})
+	}
+	return &FunctionStmt{
+		name:     generatedToken(IDENTIFIER, "init", name),
+		params:   components,
+		defaults: defaults,
+		body:     body,
+	}
+}
+
+// recordWith builds `with(a = this.a, b = this.b) { return Name(a, b); }`,
+// where Name is the class of this rather than a lookup of the record's name.
+func recordWith(name *Token, components []*Token) *FunctionStmt {
+	defaults := make([]Expr, 0, len(components))
+	args := make([]Expr, 0, len(components))
+	for _, component := range components {
+		this := &ThisExpr{keyword: generatedToken(THIS, "this", name)}
+		defaults = append(defaults, &GetExpr{object: this, name: component})
+		args = append(args, &VariableExpr{name: component})
+	}
+	class := &ClassOfExpr{object: &ThisExpr{keyword: generatedToken(THIS, "this", name)}}
+	call := &CallExpr{callee: class, paren: name, args: args, kwargs: []*KeywordArg{}}
+	return &FunctionStmt{
+		name:     generatedToken(IDENTIFIER, "with", name),
+		params:   components,
+		defaults: defaults,
+		body:     []Stmt{&ReturnStmt{keyword: generatedToken(RETURN, "return", name), value: call}},
+	}
+}
+
+func (p *Parser) enumDeclaration() Stmt {
+	name := p.consume(IDENTIFIER, "Expect enum name.")
+	p.consume(LEFT_BRACE, "Expect '{' before enum body.")
+	members := make([]*Token, 0)
+	values := make([]Expr, 0)
+	for !p.check(RIGHT_BRACE) && !p.isAtEnd() {
+		members = append(members, p.consume(IDENTIFIER, "Expect enum member name."))
+		var value Expr
+		if p.match(EQUAL) {
+			value = p.expression()
+		}
+		values = append(values, value)
+		if !p.match(COMMA) {
+			break
+		}
+	}
+	p.consume(RIGHT_BRACE, "Expect '}' after enum body.")
+	return &EnumStmt{name: name, members: members, values: values}
+}
+
+func (p *Parser) interfaceDeclaration() Stmt {
+	name := p.consume(IDENTIFIER, "Expect interface name.")
+	p.consume(LEFT_BRACE, "Expect '{' before interface body.")
+	methods := make([]*FunctionStmt, 0)
+	for !p.check(RIGHT_BRACE) && !p.isAtEnd() {
+		methods = append(methods, p.signature())
+	}
+	p.consume(RIGHT_BRACE, "Expect '}' after interface body.")
+	return &InterfaceStmt{name: name, methods: methods}
+}
+
+// signature parses a method declaration without a body, as used by
+// interfaces and abstract methods.
+func (p *Parser) signature() *FunctionStmt {
+	name := p.consume(IDENTIFIER, "Expect method name.")
+	p.consume(LEFT_PAREN, "Expect '(' after method name.")
+	params := make([]*Token, 0)
+	defaults := make([]Expr, 0)
+	if !p.check(RIGHT_PAREN) {
+		for {
+			params = append(params, p.consume(IDENTIFIER, "Expect parameter name."))
+			defaults = append(defaults, nil)
+			if !p.match(COMMA) {
+				break
+			}
+		}
+	}
+	p.consume(RIGHT_PAREN, "Expect ')' after parameters.")
+	p.consume(SEMICOLON, "Expect ';' after method signature.")
+	return &FunctionStmt{name: name, params: params, defaults: defaults, body: []Stmt{}, isAbstract: true}
+}
+
+func (p *Parser) field() *VarStmt {
+	name := p.consume(IDENTIFIER, "Expect field name.")
+	var initializer Expr
+	if p.match(EQUAL) {
+		initializer = p.expression()
+	}
+	p.consume(SEMICOLON, "Expect ';' after field declaration.")
+	return &VarStmt{name: name, initializer: initializer}
 }
 
 func (p *Parser) function(kind string) Stmt {
 	name := p.consume(IDENTIFIER, "Expect "+kind+" name.")
+	if kind == "method" && p.match(LEFT_BRACE) {
+		enclosingYields := p.yields
+		p.yields = false
+		body := p.block()
+		if p.yields {
+			p.error(name, "Can't yield from a getter.")
+		}
+		p.yields = enclosingYields
+		return &FunctionStmt{name: name, params: []*Token{}, defaults: []Expr{}, body: body, isGetter: true}
+	}
 	p.consume(LEFT_PAREN, "Expect '(' after "+kind+" name.")
 	params := make([]*Token, 0)
+	defaults := make([]Expr, 0)
+	var rest *Token
 	if !p.check(RIGHT_PAREN) {
-		if len(params) >= 255 {
-			p.error(p.peek(), "Can't have more than 255 parameters.")
-		}
-		params = append(params, p.consume(IDENTIFIER, "Expect parameter name."))
-
-		for p.match(COMMA) {
+		for {
 			if len(params) >= 255 {
 				p.error(p.peek(), "Can't have more than 255 parameters.")
 			}
+			if p.match(ELLIPSIS) {
+				rest = p.consume(IDENTIFIER, "Expect rest parameter name after '...'.")
+				break
+			}
 			params = append(params, p.consume(IDENTIFIER, "Expect parameter name."))
+			var value Expr
+			if p.match(EQUAL) {
+				value = p.expression()
+			} else if len(defaults) > 0 && defaults[len(defaults)-1] != nil {
+				p.error(p.previous(), "Parameter without a default can't follow one with a default.")
+			}
+			defaults = append(defaults, value)
+			if !p.match(COMMA) {
+				break
+			}
 		}
 	}
 	p.consume(RIGHT_PAREN, "Expect ')' after parameters.")
 	p.consume(LEFT_BRACE, "Expect '{' before "+kind+" body.")
+	enclosingYields := p.yields
+	p.yields = false
 	body := p.block()
-	return &FunctionStmt{name: name, params: params, body: body}
+	isGenerator := p.yields
+	p.yields = enclosingYields
+	return &FunctionStmt{name: name, params: params, defaults: defaults, rest: rest, body: body, isGenerator: isGenerator}
 }
 
 // ================================================================================
@@ -211,7 +547,13 @@ func (p *Parser) expression() Expr {
 }
 
 func (p *Parser) assignment() Expr {
-	expr := p.or()
+	if p.patternAhead() {
+		pattern := p.pattern()
+		equals := p.advance()
+		value := p.assignment()
+		return &DestructureExpr{pattern: pattern, equals: equals, value: value}
+	}
+	expr := p.coalesce()
 	if p.match(EQUAL) {
 		equals := p.previous()
 		value := p.assignment()
@@ -221,11 +563,24 @@ func (p *Parser) assignment() Expr {
 		if expr, ok := expr.(*GetExpr); ok {
 			return &SetExpr{object: expr.object, name: expr.name, value: value}
 		}
+		if expr, ok := expr.(*IndexExpr); ok {
+			return &IndexSetExpr{object: expr.object, bracket: expr.bracket, index: expr.index, value: value}
+		}
 		p.error(equals, "Invalid assignment target.")
 	}
 	return expr
 }
 
+func (p *Parser) coalesce() Expr {
+	expr := p.or()
+	for p.match(QUESTION_QUESTION) {
+		operator := p.previous()
+		right := p.or()
+		expr = &LogicalExpr{left: expr, operator: operator, right: right}
+	}
+	return expr
+}
+
 func (p *Parser) or() Expr {
 	expr := p.and()
 	for p.match(OR) {
@@ -257,15 +612,30 @@ func (p *Parser) equality() Expr {
 }
 
 func (p *Parser) comparison() Expr {
-	expr := p.term()
-	for p.match(GREATER, GREATER_EQUAL, LESS, LESS_EQUAL) {
+	expr := p.rangeExpr()
+	for p.match(GREATER, GREATER_EQUAL, LESS, LESS_EQUAL, IN, IS) {
 		operator := p.previous()
-		right := p.term()
+		right := p.rangeExpr()
 		expr = &BinaryExpr{left: expr, operator: operator, right: right}
 	}
 	return expr
 }
 
+func (p *Parser) rangeExpr() Expr {
+	expr := p.term()
+	if p.match(DOT_DOT, DOT_DOT_EQUAL) {
+		operator := p.previous()
+		stop := p.term()
+		var step Expr
+		if p.check(IDENTIFIER) && p.peek().lexeme == "step" {
+			p.advance()
+			step = p.term()
+		}
+		expr = &RangeExpr{start: expr, operator: operator, stop: stop, step: step}
+	}
+	return expr
+}
+
 func (p *Parser) term() Expr {
 	expr := p.factor()
 	for p.match(MINUS, PLUS) {
@@ -295,34 +665,86 @@ func (p *Parser) unary() Expr {
 	return p.call()
 }
 
+// propertyName consumes the name after '.' or '?.', which may also be 'with'
+// so that the copy method of records can be called.
+func (p *Parser) propertyName(message string) *Token {
+	if p.match(WITH) {
+		return p.previous()
+	}
+	return p.consume(IDENTIFIER, message)
+}
+
 func (p *Parser) call() Expr {
 	expr := p.primary()
+	optional := false
 	for {
 		if p.match(LEFT_PAREN) {
 			expr = p.finishCall(expr)
 		} else if p.match(DOT) {
-			name := p.consume(IDENTIFIER, "Expect property name after '.'.")
+			name := p.propertyName("Expect property name after '.'.")
 			expr = &GetExpr{object: expr, name: name}
+		} else if p.match(QUESTION_DOT) {
+			name := p.propertyName("Expect property name after '?.'.")
+			expr = &GetExpr{object: expr, name: name, optional: true}
+			optional = true
+		} else if p.match(LEFT_BRACKET) {
+			expr = p.finishIndex(expr)
 		} else {
 			break
 		}
 	}
+	if optional {
+		return &OptionalChainExpr{expr: expr}
+	}
 	return expr
 }
 
+func (p *Parser) finishIndex(object Expr) Expr {
+	var start Expr
+	if !p.check(COLON) {
+		start = p.expression()
+		if !p.check(COLON) {
+			bracket := p.consume(RIGHT_BRACKET, "Expect ']' after index.")
+			return &IndexExpr{object: object, bracket: bracket, index: start}
+		}
+	}
+	p.consume(COLON, "Expect ':' in slice.")
+	var stop, step Expr
+	if !p.check(COLON) && !p.check(RIGHT_BRACKET) {
+		stop = p.expression()
+	}
+	if p.match(COLON) && !p.check(RIGHT_BRACKET) {
+		step = p.expression()
+	}
+	bracket := p.consume(RIGHT_BRACKET, "Expect ']' after slice.")
+	return &SliceExpr{object: object, bracket: bracket, start: start, stop: stop, step: step}
+}
+
 func (p *Parser) finishCall(callee Expr) Expr {
 	args := make([]Expr, 0)
+	kwargs := make([]*KeywordArg, 0)
 	if !p.check(RIGHT_PAREN) {
-		args = append(args, p.expression())
-		for p.match(COMMA) {
-			if len(args) >= 255 {
+		for {
+			if len(args)+len(kwargs) >= 255 {
 				p.error(p.peek(), "Can't have more than 255 arguments.")
 			}
-			args = append(args, p.expression())
+			if p.check(IDENTIFIER) && p.checkNext(COLON) {
+				name := p.advance()
+				p.advance()
+				kwargs = append(kwargs, &KeywordArg{name: name, value: p.expression()})
+			} else {
+				if len(kwargs) > 0 {
+					p.error(p.peek(), "Positional argument can't follow a keyword argument.")
+				}
+				args = append(args, p.spreadOrExpression())
+			}
+			if !p.match(COMMA) {
+				break
+			}
 		}
 	}
 	paren := p.consume(RIGHT_PAREN, "Expect ')' after arguments.")
-	return &CallExpr{callee: callee, paren: paren, args: args}
+	return &CallExpr{callee: callee, paren: paren, args: args, kwargs: kwargs}
 }
 
 func (p *Parser) primary() Expr {
@@ -351,15 +773,127 @@ func (p *Parser) primary() Expr {
 		return &VariableExpr{name: p.previous()}
 	}
 	if p.match(LEFT_PAREN) {
+		paren := p.previous()
 		expr := p.expression()
+		if p.match(COMMA) {
+			elements := []Expr{expr}
+			for !p.check(RIGHT_PAREN) && !p.isAtEnd() {
+				elements = append(elements, p.expression())
+				if !p.match(COMMA) {
+					break
+				}
+			}
+			p.consume(RIGHT_PAREN, "Expect ')' after tuple elements.")
+			return &TupleExpr{paren: paren, elements: elements}
+		}
 		p.consume(RIGHT_PAREN, "Expect ')' after expression.")
 		return &GroupingExpr{expr: expr}
 	}
+	if p.match(LEFT_BRACKET) {
+		return p.list()
+	}
+	if p.match(LEFT_BRACE) {
+		return p.mapLiteral()
+	}
 
 	p.error(p.peek(), "expected an expression. Last token was: "+p.peek().lexeme)
 	return nil
 }
 
+func (p *Parser) list() Expr {
+	elements := make([]Expr, 0)
+	for !p.check(RIGHT_BRACKET) && !p.isAtEnd() {
+		elements = append(elements, p.spreadOrExpression())
+		if !p.match(COMMA) {
+			break
+		}
+	}
+	bracket := p.consume(RIGHT_BRACKET, "Expect ']' after list elements.")
+	return &ListExpr{bracket: bracket, elements: elements}
+}
+
+func (p *Parser) spreadOrExpression() Expr {
+	if p.match(ELLIPSIS) {
+		return &SpreadExpr{ellipsis: p.previous(), expr: p.expression()}
+	}
+	return p.expression()
+}
+
+func (p *Parser) mapLiteral() Expr {
+	keys := make([]Expr, 0)
+	values := make([]Expr, 0)
+	for !p.check(RIGHT_BRACE) && !p.isAtEnd() {
+		if p.match(ELLIPSIS) {
+			keys = append(keys, &SpreadExpr{ellipsis: p.previous(), expr: p.expression()})
+			values = append(values, nil)
+			if !p.match(COMMA) {
+				break
+			}
+			continue
+		}
+		keys = append(keys, p.expression())
+		p.consume(COLON, "Expect ':' after map key.")
+		values = append(values, p.expression())
+		if !p.match(COMMA) {
+			break
+		}
+	}
+	brace := p.consume(RIGHT_BRACE, "Expect '}' after map entries.")
+	return &MapExpr{brace: brace, keys: keys, values: values}
+}
+
+func (p *Parser) pattern() *Pattern {
+	open := p.advance()
+	targets := make([]*VariableExpr, 0)
+	for {
+		name := p.consume(IDENTIFIER, "Expect variable name in destructuring pattern.")
+		targets = append(targets, &VariableExpr{name: name})
+		if !p.match(COMMA) {
+			break
+		}
+	}
+	switch open.ttype {
+	case LEFT_PAREN:
+		p.consume(RIGHT_PAREN, "Expect ')' after destructuring pattern.")
+	case LEFT_BRACKET:
+		p.consume(RIGHT_BRACKET, "Expect ']' after destructuring pattern.")
+	case LEFT_BRACE:
+		p.consume(RIGHT_BRACE, "Expect '}' after destructuring pattern.")
+	}
+	return &Pattern{open: open, targets: targets}
+}
+
+// patternAhead reports whether the upcoming tokens are a destructuring
+// pattern followed by '=', without consuming them. A parenthesised pattern
+// needs at least two names so that `(a) = b` stays an ordinary grouping.
+func (p *Parser) patternAhead() bool {
+	var closer TokenType
+	switch p.peek().ttype {
+	case LEFT_PAREN:
+		closer = RIGHT_PAREN
+	case LEFT_BRACKET:
+		closer = RIGHT_BRACKET
+	case LEFT_BRACE:
+		closer = RIGHT_BRACE
+	default:
+		return false
+	}
+	names := 0
+	i := p.current + 1
+	for p.tokens[i].ttype == IDENTIFIER {
+		names++
+		i++
+		if p.tokens[i].ttype != COMMA {
+			break
+		}
+		i++
+	}
+	if closer == RIGHT_PAREN && names < 2 {
+		return false
+	}
+	return names > 0 && p.tokens[i].ttype == closer && p.tokens[i+1].ttype == EQUAL
+}
+
 // ================================================================================
 // ### HELPERS
 // ================================================================================
@@ -386,6 +920,12 @@ func (p *Parser) check(ttype TokenType) bool {
 	}
 	return p.peek().ttype == ttype
 }
+func (p *Parser) checkNext(ttype TokenType) bool {
+	if p.isAtEnd() || p.current+1 >= len(p.tokens) {
+		return false
+	}
+	return p.tokens[p.current+1].ttype == ttype
+}
 func (p *Parser) advance() *Token {
 	if !p.isAtEnd() {
 		p.current++
@@ -411,7 +951,7 @@ func (p *Parser) synchronize() {
 			return
 		}
 		switch p.peek().ttype {
-		case CLASS, FUN, VAR, FOR, IF, WHILE, PRINT, RETURN:
+		case IMPORT, EXPORT, CLASS, TRAIT, INTERFACE, ENUM, RECORD, FUN, VAR, CONST, FOR, IF, WHILE, PRINT, RETURN, YIELD:
 			return
 		}
 		p.advance()
@@ -419,6 +959,6 @@ func (p *Parser) synchronize() {
 }
 
 func (p *Parser) error(token *Token, msg string) {
-	fmt.Printf("[line %d] error: %s\n", token.line, msg)
+	fmt.Printf("[%s] error: %s\n", token.location(), msg)
 	p.hadErr = true
 }
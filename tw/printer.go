@@ -37,26 +37,52 @@ func (p *Printer) visitClassStmt(stmt *ClassStmt) (StmtReturn, error) {
 	sb := new(strings.Builder)
 	sb.WriteString("(class ")
 	sb.WriteString(stmt.name.lexeme)
+	for _, f := range stmt.classFields {
+		sb.WriteString(" (class " + p.PrintStmt(f) + ")")
+	}
+	for _, m := range stmt.classMethods {
+		sb.WriteString(" (class " + p.PrintStmt(m) + ")")
+	}
 	for _, m := range stmt.methods {
 		v, _ := m.Accept(p)
 		sb.WriteString(fmt.Sprintf("%v", v))
 	}
+	for _, m := range stmt.setters {
+		sb.WriteString(" (set " + p.PrintStmt(m) + ")")
+	}
 	sb.WriteString(")")
 	return StmtReturn{value: sb.String()}, nil
 }
 
+func (p *Printer) visitDestructureStmt(stmt *DestructureStmt) (StmtReturn, error) {
+	return StmtReturn{value: p.parenthesize("var", p.pattern(stmt.pattern), "=", stmt.initializer)}, nil
+}
+
 func (p *Printer) visitExpressionStmt(stmt *ExpressionStmt) (StmtReturn, error) {
 	return StmtReturn{value: p.parenthesize(";", stmt.expr)}, nil
 }
 
+func (p *Printer) visitForInStmt(stmt *ForInStmt) (StmtReturn, error) {
+	return StmtReturn{value: p.parenthesize("for-in", stmt.name.lexeme, stmt.iterable, stmt.body)}, nil
+}
+
 func (p *Printer) visitFunctionStmt(stmt *FunctionStmt) (StmtReturn, error) {
 	sb := new(strings.Builder)
 	sb.WriteString("(fun " + stmt.name.lexeme + "(")
-	for _, param := range stmt.params {
+	for i, param := range stmt.params {
 		if param != stmt.params[0] {
 			sb.WriteString(" ")
 		}
 		sb.WriteString(param.lexeme)
+		if stmt.defaults[i] != nil {
+			sb.WriteString("=" + p.PrintExpr(stmt.defaults[i]))
+		}
+	}
+	if stmt.rest != nil {
+		if len(stmt.params) > 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString("..." + stmt.rest.lexeme)
 	}
 	sb.WriteString(") ")
 	for _, s := range stmt.body {
@@ -74,6 +100,42 @@ func (p *Printer) visitIfStmt(stmt *IfStmt) (StmtReturn, error) {
 	return StmtReturn{value: p.parenthesize("if", stmt.condition, stmt.thenBranch)}, nil
 }
 
+func (p *Printer) visitEnumStmt(stmt *EnumStmt) (StmtReturn, error) {
+	sb := new(strings.Builder)
+	sb.WriteString("(enum ")
+	sb.WriteString(stmt.name.lexeme)
+	for k, member := range stmt.members {
+		if stmt.values[k] != nil {
+			sb.WriteString(p.parenthesize("=", member, stmt.values[k]))
+		} else {
+			sb.WriteString(" " + member.lexeme)
+		}
+	}
+	sb.WriteString(")")
+	return StmtReturn{value: sb.String()}, nil
+}
+
+func (p *Printer) visitImportStmt(stmt *ImportStmt) (StmtReturn, error) {
+	return StmtReturn{value: p.parenthesize("import", stmt.path, "as", stmt.name)}, nil
+}
+
+func (p *Printer) visitExportStmt(stmt *ExportStmt) (StmtReturn, error) {
+	v, _ := stmt.declaration.Accept(p)
+	return StmtReturn{value: fmt.Sprintf("(export %v)", v.value)}, nil
+}
+
+func (p *Printer) visitInterfaceStmt(stmt *InterfaceStmt) (StmtReturn, error) {
+	sb := new(strings.Builder)
+	sb.WriteString("(interface ")
+	sb.WriteString(stmt.name.lexeme)
+	for _, m := range stmt.methods {
+		v, _ := m.Accept(p)
+		sb.WriteString(fmt.Sprintf("%v", v))
+	}
+	sb.WriteString(")")
+	return StmtReturn{value: sb.String()}, nil
+}
+
 func (p *Printer) visitPrintStmt(stmt *PrintStmt) (StmtReturn, error) {
 	return StmtReturn{value: p.parenthesize("print", stmt.expr)}, nil
 }
@@ -85,7 +147,22 @@ func (p *Printer) visitReturnStmt(stmt *ReturnStmt) (StmtReturn, error) {
 	return StmtReturn{value: p.parenthesize("return", stmt.value)}, nil
 }
 
+func (p *Printer) visitTraitStmt(stmt *TraitStmt) (StmtReturn, error) {
+	sb := new(strings.Builder)
+	sb.WriteString("(trait ")
+	sb.WriteString(stmt.name.lexeme)
+	for _, m := range stmt.methods {
+		v, _ := m.Accept(p)
+		sb.WriteString(fmt.Sprintf("%v", v))
+	}
+	sb.WriteString(")")
+	return StmtReturn{value: sb.String()}, nil
+}
+
 func (p *Printer) visitVarStmt(stmt *VarStmt) (StmtReturn, error) {
+	if stmt.isConst {
+		return StmtReturn{value: p.parenthesize("const", stmt.name, "=", stmt.initializer)}, nil
+	}
 	if stmt.initializer != nil {
 		return StmtReturn{value: p.parenthesize("var", stmt.name, "=", stmt.initializer)}, nil
 	}
@@ -96,6 +173,13 @@ func (p *Printer) visitWhileStmt(stmt *WhileStmt) (StmtReturn, error) {
 	return StmtReturn{value: p.parenthesize("while", stmt.condition, stmt.body)}, nil
 }
 
+func (p *Printer) visitYieldStmt(stmt *YieldStmt) (StmtReturn, error) {
+	if stmt.value == nil {
+		return StmtReturn{value: "(yield)"}, nil
+	}
+	return StmtReturn{value: p.parenthesize("yield", stmt.value)}, nil
+}
+
 // ================================================================================
 // ### EXPRESSIONS
 // ================================================================================
@@ -109,10 +193,25 @@ func (p *Printer) visitBinaryExpr(expr *BinaryExpr) (interface{}, error) {
 }
 
 func (p *Printer) visitCallExpr(expr *CallExpr) (interface{}, error) {
-	return p.parenthesize("call", expr.callee, expr.paren, expr.args), nil
+	kwargs := make([]interface{}, 0, len(expr.kwargs))
+	for _, kwarg := range expr.kwargs {
+		kwargs = append(kwargs, kwarg.name.lexeme+":", kwarg.value)
+	}
+	return p.parenthesize("call", expr.callee, expr.paren, expr.args, kwargs), nil
+}
+
+func (p *Printer) visitClassOfExpr(expr *ClassOfExpr) (interface{}, error) {
+	return p.parenthesize("classof", expr.object), nil
+}
+
+func (p *Printer) visitDestructureExpr(expr *DestructureExpr) (interface{}, error) {
+	return p.parenthesize("=", p.pattern(expr.pattern), expr.value), nil
 }
 
 func (p *Printer) visitGetExpr(expr *GetExpr) (interface{}, error) {
+	if expr.optional {
+		return p.parenthesize("?.", expr.object, expr.name), nil
+	}
 	return p.parenthesize(".", expr.object, expr.name), nil
 }
 
@@ -120,6 +219,33 @@ func (p *Printer) visitGroupingExpr(expr *GroupingExpr) (interface{}, error) {
 	return p.parenthesize("group", expr.expr), nil
 }
 
+func (p *Printer) visitIndexExpr(expr *IndexExpr) (interface{}, error) {
+	return p.parenthesize("[]", expr.object, expr.index), nil
+}
+
+func (p *Printer) visitIndexSetExpr(expr *IndexSetExpr) (interface{}, error) {
+	return p.parenthesize("[]=", expr.object, expr.index, expr.value), nil
+}
+
+func (p *Printer) visitListExpr(expr *ListExpr) (interface{}, error) {
+	elements := make([]interface{}, len(expr.elements))
+	for i, element := range expr.elements {
+		elements[i] = element
+	}
+	return p.parenthesize("list", elements...), nil
+}
+
+func (p *Printer) visitMapExpr(expr *MapExpr) (interface{}, error) {
+	entries := make([]interface{}, 0, len(expr.keys)*2)
+	for i, key := range expr.keys {
+		entries = append(entries, key)
+		if expr.values[i] != nil {
+			entries = append(entries, expr.values[i])
+		}
+	}
+	return p.parenthesize("map", entries...), nil
+}
+
 func (p *Printer) visitLiteralExpr(expr *LiteralExpr) (interface{}, error) {
 	if expr.value == nil {
 		return "nil", nil
@@ -131,14 +257,53 @@ func (p *Printer) visitLogicalExpr(expr *LogicalExpr) (interface{}, error) {
 	return p.parenthesize(expr.operator.lexeme, expr.left, expr.right), nil
 }
 
+func (p *Printer) visitOptionalChainExpr(expr *OptionalChainExpr) (interface{}, error) {
+	return p.parenthesize("?", expr.expr), nil
+}
+
+func (p *Printer) visitRangeExpr(expr *RangeExpr) (interface{}, error) {
+	if expr.step != nil {
+		return p.parenthesize(expr.operator.lexeme, expr.start, expr.stop, expr.step), nil
+	}
+	return p.parenthesize(expr.operator.lexeme, expr.start, expr.stop), nil
+}
+
 func (p *Printer) visitSetExpr(expr *SetExpr) (interface{}, error) {
 	return p.parenthesize("=", expr.object, expr.name, expr.value), nil
 }
 
+func (p *Printer) visitSliceExpr(expr *SliceExpr) (interface{}, error) {
+	bounds := make([]interface{}, 0, 3)
+	for _, bound := range []Expr{expr.start, expr.stop, expr.step} {
+		if bound == nil {
+			bounds = append(bounds, "nil")
+		} else {
+			bounds = append(bounds, bound)
+		}
+	}
+	return p.parenthesize("[:]", expr.object, bounds), nil
+}
+
+func (p *Printer) visitSpreadExpr(expr *SpreadExpr) (interface{}, error) {
+	return p.parenthesize("...", expr.expr), nil
+}
+
+func (p *Printer) visitSuperExpr(expr *SuperExpr) (interface{}, error) {
+	return "super." + expr.method.lexeme, nil
+}
+
 func (p *Printer) visitThisExpr(expr *ThisExpr) (interface{}, error) {
 	return "this", nil
 }
 
+func (p *Printer) visitTupleExpr(expr *TupleExpr) (interface{}, error) {
+	elements := make([]interface{}, len(expr.elements))
+	for i, element := range expr.elements {
+		elements[i] = element
+	}
+	return p.parenthesize("tuple", elements...), nil
+}
+
 func (p *Printer) visitUnaryExpr(expr *UnaryExpr) (interface{}, error) {
 	return p.parenthesize(expr.operator.lexeme, expr.right), nil
 }
@@ -151,6 +316,14 @@ func (p *Printer) visitVariableExpr(expr *VariableExpr) (interface{}, error) {
 // ### HELPERS
 // ================================================================================
 
+func (p *Printer) pattern(pattern *Pattern) string {
+	names := make([]interface{}, len(pattern.targets))
+	for i, target := range pattern.targets {
+		names[i] = target.name.lexeme
+	}
+	return p.parenthesize(pattern.open.lexeme, names...)
+}
+
 func (p *Printer) parenthesize(name string, parts ...interface{}) string {
 	sb := new(strings.Builder)
 	sb.WriteString("(" + name)
@@ -5,16 +5,46 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
 type Program struct {
 	interpreter *Interpreter
+	searchPath  []string
+	// modules caches loaded modules by absolute path, and loading holds the
+	// chain of files being compiled, to detect import cycles.
+	modules map[string]*Module
+	loading []string
 }
 
-func NewProgram() *Program {
-	return &Program{
+type ProgramOption func(p *Program)
+
+// WithMaxCallDepth limits how deeply Lox calls may nest. Zero disables the
+// limit.
+func WithMaxCallDepth(depth int) ProgramOption {
+	return func(p *Program) {
+		p.interpreter.SetMaxCallDepth(depth)
+	}
+}
+
+// WithSearchPath adds directories in which imports are looked up when they
+// aren't found relative to the importing file.
+func WithSearchPath(dirs ...string) ProgramOption {
+	return func(p *Program) {
+		p.searchPath = append(p.searchPath, dirs...)
+	}
+}
+
+func NewProgram(opts ...ProgramOption) *Program {
+	p := &Program{
 		interpreter: NewInterpreter(),
+		modules:     make(map[string]*Module),
+	}
+	for _, opt := range opts {
+		opt(p)
 	}
+	return p
 }
 
 func (p *Program) RunFile(path string) error {
@@ -23,7 +53,7 @@ func (p *Program) RunFile(path string) error {
 		return fmt.Errorf("error reading file: %w", err)
 	}
 
-	err = p.run(string(file))
+	err = p.run(string(file), path)
 
 	if errors.Is(err, ErrCompiler) {
 		log.Println(err)
@@ -38,10 +68,35 @@ func (p *Program) RunFile(path string) error {
 	return nil
 }
 
-func (p *Program) run(source string) error {
+func (p *Program) run(source string, path string) error {
+	statements, err := p.compile(source, path)
+	if err != nil {
+		return err
+	}
+
+	runtimeErr := p.interpreter.Interpret(statements)
+	if runtimeErr != nil {
+		fmt.Println(runtimeErr)
+		return ErrRuntime
+	}
+
+	return nil
+}
+
+// compile scans, parses and resolves the source of the file at path, loading
+// the modules it imports first. Diagnostics in modules name the module's file
+// relative to the main script.
+func (p *Program) compile(source string, path string) ([]Stmt, error) {
 	var compileErr bool
 
-	scanner := NewScanner(source)
+	file := ""
+	if len(p.loading) > 0 {
+		file = path
+		if rel, err := filepath.Rel(filepath.Dir(p.loading[0]), path); err == nil {
+			file = rel
+		}
+	}
+	scanner := NewScanner(source, file)
 	tokens, err := scanner.Scan()
 	if err {
 		compileErr = true
@@ -60,7 +115,20 @@ func (p *Program) run(source string) error {
 	// }
 
 	if compileErr {
-		return ErrCompiler
+		return nil, ErrCompiler
+	}
+
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	p.loading = append(p.loading, path)
+	defer func() { p.loading = p.loading[:len(p.loading)-1] }()
+	for _, stmt := range statements {
+		if stmt, ok := stmt.(*ImportStmt); ok {
+			if err := p.load(stmt, path); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	resolver := NewResolver(p.interpreter)
@@ -70,14 +138,67 @@ func (p *Program) run(source string) error {
 	}
 
 	if compileErr {
-		return ErrCompiler
+		return nil, ErrCompiler
 	}
 
-	runtimeErr := p.interpreter.Interpret(statements)
-	if runtimeErr != nil {
-		fmt.Println(runtimeErr)
-		return ErrRuntime
+	return statements, nil
+}
+
+// load compiles the module imported by stmt, unless it is already cached.
+func (p *Program) load(stmt *ImportStmt, from string) error {
+	name := stmt.path.literal.(string)
+	path, ok := p.findModule(name, from)
+	if !ok {
+		fmt.Println("["+stmt.path.location(), "] Error Cannot find module '"+name+"'.")
+		return ErrCompiler
+	}
+	if module, ok := p.modules[path]; ok {
+		p.interpreter.Import(stmt, module)
+		return nil
+	}
+	for k, loading := range p.loading {
+		if loading == path {
+			chain := make([]string, 0, len(p.loading)-k+1)
+			for _, file := range append(p.loading[k:], path) {
+				chain = append(chain, filepath.Base(file))
+			}
+			fmt.Println("["+stmt.path.location(), "] Error Import cycle: "+strings.Join(chain, " -> ")+".")
+			return ErrCompiler
+		}
 	}
 
+	source, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Println("["+stmt.path.location(), "] Error Cannot read module '"+name+"'.")
+		return ErrCompiler
+	}
+	statements, err := p.compile(string(source), path)
+	if err != nil {
+		return err
+	}
+	module := NewModule(path, statements)
+	p.modules[path] = module
+	p.interpreter.Import(stmt, module)
 	return nil
 }
+
+// findModule looks an import up relative to the importing file, then in each
+// directory of the search path, returning its absolute path.
+func (p *Program) findModule(name string, from string) (string, bool) {
+	candidates := []string{name}
+	if !filepath.IsAbs(name) {
+		candidates = []string{filepath.Join(filepath.Dir(from), name)}
+		for _, dir := range p.searchPath {
+			candidates = append(candidates, filepath.Join(dir, name))
+		}
+	}
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			if abs, err := filepath.Abs(candidate); err == nil {
+				return abs, true
+			}
+			return candidate, true
+		}
+	}
+	return "", false
+}
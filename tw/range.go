@@ -0,0 +1,132 @@
+package tw
+
+import "fmt"
+
+// Range is a lazy sequence of numbers produced by `start..stop` or
+// `start..=stop`, optionally with a step. No list is ever allocated for it.
+type Range struct {
+	start     int
+	stop      int
+	step      int
+	inclusive bool
+}
+
+func NewRange(start, stop, step int, inclusive bool) *Range {
+	return &Range{
+		start:     start,
+		stop:      stop,
+		step:      step,
+		inclusive: inclusive,
+	}
+}
+
+// end returns the exclusive bound of the range.
+func (r *Range) end() int {
+	if !r.inclusive {
+		return r.stop
+	}
+	if r.step > 0 {
+		return r.stop + 1
+	}
+	return r.stop - 1
+}
+
+func (r *Range) Len() int {
+	var n int
+	if r.step > 0 {
+		n = (r.end() - r.start + r.step - 1) / r.step
+	} else {
+		n = (r.start - r.end() - r.step - 1) / -r.step
+	}
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+func (r *Range) Contains(value interface{}) bool {
+	n, ok := value.(int)
+	if !ok {
+		return false
+	}
+	if r.step > 0 && (n < r.start || n >= r.end()) {
+		return false
+	}
+	if r.step < 0 && (n > r.start || n <= r.end()) {
+		return false
+	}
+	return (n-r.start)%r.step == 0
+}
+
+func (r *Range) Get(interpreter *Interpreter, name *Token) (interface{}, error) {
+	switch name.lexeme {
+	case "contains":
+		return NewNativeFunction("contains", 1, 1, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+			return r.Contains(args[0]), nil
+		}), nil
+	case "len":
+		return NewNativeFunction("len", 0, 0, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+			return r.Len(), nil
+		}), nil
+	}
+	return nil, fmt.Errorf("undefined property '%s'", name.lexeme)
+}
+
+func (r *Range) GetIndex(interpreter *Interpreter, index interface{}) (interface{}, error) {
+	i, err := sequenceIndex("Range", index, r.Len())
+	if err != nil {
+		return nil, err
+	}
+	return r.start + i*r.step, nil
+}
+
+func (r *Range) SetIndex(interpreter *Interpreter, index interface{}, value interface{}) error {
+	return fmt.Errorf("Ranges are immutable.")
+}
+
+func (r *Range) Iterator() Iterator {
+	return &rangeIterator{rng: r, next: r.start}
+}
+
+// sliceBounds returns the range as the start, stop and step of a slice, so
+// that `xs[1..3]` is the same as `xs[1:3]`. An inclusive range running up to
+// the last element, or down to the first, has no exclusive bound that slicing
+// wouldn't read as an index, so its stop is left open.
+func (r *Range) sliceBounds() (interface{}, interface{}, interface{}) {
+	if r.inclusive && r.stop == -1 && r.step > 0 {
+		return r.start, nil, r.step
+	}
+	if r.inclusive && r.stop == 0 && r.step < 0 {
+		return r.start, nil, r.step
+	}
+	return r.start, r.end(), r.step
+}
+
+func (r *Range) String() string {
+	op := ".."
+	if r.inclusive {
+		op = "..="
+	}
+	if r.step != 1 {
+		return fmt.Sprintf("%d%s%d step %d", r.start, op, r.stop, r.step)
+	}
+	return fmt.Sprintf("%d%s%d", r.start, op, r.stop)
+}
+
+type rangeIterator struct {
+	rng  *Range
+	next int
+}
+
+func (it *rangeIterator) HasNext() (bool, error) {
+	if it.rng.step > 0 {
+		return it.next < it.rng.end(), nil
+	}
+	return it.next > it.rng.end(), nil
+}
+
+func (it *rangeIterator) Next() (interface{}, error) {
+	value := it.next
+	it.next += it.rng.step
+	return value, nil
+}
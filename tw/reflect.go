@@ -0,0 +1,162 @@
+package tw
+
+import (
+	"fmt"
+	"sort"
+)
+
+// defineReflection adds the type-introspection natives to the globals.
+func defineReflection(globals *Environment) {
+	globals.Define("type", NewNativeFunction("type", 1, 1, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+		return typeOf(args[0]), nil
+	}))
+
+	globals.Define("fields", NewNativeFunction("fields", 1, 1, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+		switch object := args[0].(type) {
+		case *Instance:
+			return sortedNames(object.fields), nil
+		case *Class:
+			return sortedNames(object.fields), nil
+		}
+		return nil, fmt.Errorf("fields() expects an instance or a class")
+	}))
+
+	globals.Define("methods", NewNativeFunction("methods", 1, 1, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+		class, ok := args[0].(*Class)
+		if !ok {
+			return nil, fmt.Errorf("methods() expects a class")
+		}
+		names := make(map[string]interface{})
+		for c := class; c != nil; c = c.superclass {
+			for name := range c.methods {
+				if !isPrivate(name) {
+					names[name] = nil
+				}
+			}
+		}
+		return sortedNames(names), nil
+	}))
+
+	globals.Define("hasField", NewNativeFunction("hasField", 2, 2, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+		instance, name, err := fieldArgs("hasField", args)
+		if err != nil {
+			return nil, err
+		}
+		_, ok := instance.fields[name]
+		return ok, nil
+	}))
+
+	globals.Define("getField", NewNativeFunction("getField", 2, 2, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+		instance, name, err := fieldArgs("getField", args)
+		if err != nil {
+			return nil, err
+		}
+		value, ok := instance.fields[name]
+		if !ok {
+			return nil, fmt.Errorf("%s has no field '%s'", instance.class.name, name)
+		}
+		return value, nil
+	}))
+
+	globals.Define("setField", NewNativeFunction("setField", 3, 3, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+		instance, name, err := fieldArgs("setField", args)
+		if err != nil {
+			return nil, err
+		}
+		return nil, instance.Set(interpreter, NewToken(IDENTIFIER, name, nil, 0), args[2])
+	}))
+
+	globals.Define("superclassOf", NewNativeFunction("superclassOf", 1, 1, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+		class, ok := args[0].(*Class)
+		if !ok {
+			return nil, fmt.Errorf("superclassOf() expects a class")
+		}
+		if class.superclass == nil {
+			return nil, nil
+		}
+		return class.superclass, nil
+	}))
+
+	// arity counts the arguments a callable requires, leaving out those with
+	// defaults and any rest parameter.
+	globals.Define("arity", NewNativeFunction("arity", 1, 1, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+		callable, ok := args[0].(Callable)
+		if !ok {
+			return nil, fmt.Errorf("arity() expects a function")
+		}
+		min, _ := callable.Arity()
+		return min, nil
+	}))
+}
+
+// typeOf returns the name of the type of value. Instances are named after
+// their class and enum members after their enum.
+func typeOf(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return "bool"
+	case int:
+		return "number"
+	case string:
+		return "string"
+	case *List:
+		return "list"
+	case *Map:
+		return "map"
+	case *Tuple:
+		return "tuple"
+	case *Range:
+		return "range"
+	case *Generator:
+		return "generator"
+	case *Class:
+		return "class"
+	case *Trait:
+		return "trait"
+	case *Interface:
+		return "interface"
+	case *Module:
+		return "module"
+	case *Enum:
+		return "enum"
+	case *EnumMember:
+		return value.enum.name
+	case *Instance:
+		return value.class.name
+	case Callable:
+		return "function"
+	}
+	return "unknown"
+}
+
+// fieldArgs checks the instance and field name arguments of the field
+// natives. Private members are never reachable through reflection.
+func fieldArgs(native string, args []interface{}) (*Instance, string, error) {
+	instance, ok := args[0].(*Instance)
+	if !ok {
+		return nil, "", fmt.Errorf("%s() expects an instance", native)
+	}
+	name, ok := args[1].(string)
+	if !ok {
+		return nil, "", fmt.Errorf("%s() expects a field name", native)
+	}
+	if isPrivate(name) {
+		return nil, "", fmt.Errorf("cannot access private member '%s' of %s", name, instance.class.name)
+	}
+	return instance, name, nil
+}
+
+func sortedNames(values map[string]interface{}) *List {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	elements := make([]interface{}, len(names))
+	for k, name := range names {
+		elements[k] = name
+	}
+	return NewList(elements)
+}
@@ -1,6 +1,9 @@
 package tw
 
-import "fmt"
+import (
+	"fmt"
+	"log"
+)
 
 type FunctionType string
 
@@ -17,13 +20,23 @@ const (
 	ClassNone     ClassType = "none"
 	ClassClass    ClassType = "class"
 	ClassSubclass ClassType = "subclass"
+	ClassTrait    ClassType = "trait"
 )
 
 type Resolver struct {
 	interpreter  *Interpreter
 	scopes       Stack[map[string]bool]
+	constants    Stack[map[string]bool]
+	globalConsts map[string]bool
+	traits       map[string]*TraitStmt
+	classes      map[string]*ClassStmt
+	// declared holds the fields the current class is known to have, or nil
+	// when its fields aren't checked.
+	declared     map[string]bool
 	currentFn    FunctionType
 	currentClass ClassType
+	inGenerator  bool
+	inStatic     bool
 	hadErr       bool
 }
 
@@ -31,6 +44,10 @@ func NewResolver(interpreter *Interpreter) *Resolver {
 	return &Resolver{
 		interpreter:  interpreter,
 		scopes:       Stack[map[string]bool]{},
+		constants:    Stack[map[string]bool]{},
+		globalConsts: make(map[string]bool),
+		traits:       make(map[string]*TraitStmt),
+		classes:      make(map[string]*ClassStmt),
 		currentFn:    FunctionNone,
 		currentClass: ClassNone,
 		hadErr:       false,
@@ -64,19 +81,79 @@ func (r *Resolver) resolveLocal(expr Expr, name *Token) {
 	}
 }
 
+// checkAssignable reports an error if name resolves to a constant.
+func (r *Resolver) checkAssignable(name *Token) {
+	for i := r.scopes.Size() - 1; i >= 0; i-- {
+		if _, ok := r.scopes.Get(i)[name.lexeme]; ok {
+			if r.constants.Get(i)[name.lexeme] {
+				r.error(name, "Cannot assign to constant '"+name.lexeme+"'.")
+			}
+			return
+		}
+	}
+	if r.globalConsts[name.lexeme] {
+		r.error(name, "Cannot assign to constant '"+name.lexeme+"'.")
+	}
+}
+
+// checkTraitConflicts reports methods that more than one of a class's traits
+// define without the class overriding them. Only traits whose declarations
+// the resolver has already seen can be checked here; the interpreter checks
+// the rest when the class is defined.
+func (r *Resolver) checkTraitConflicts(stmt *ClassStmt) {
+	own := make(map[string]bool)
+	for _, method := range stmt.methods {
+		own[method.name.lexeme] = true
+	}
+	providers := make(map[string]string)
+	seen := make(map[string]bool)
+	for _, trait := range stmt.traits {
+		if seen[trait.name.lexeme] {
+			r.error(trait.name, "Trait '"+trait.name.lexeme+"' is already mixed in.")
+			continue
+		}
+		seen[trait.name.lexeme] = true
+		decl, ok := r.traits[trait.name.lexeme]
+		if !ok {
+			continue
+		}
+		for _, method := range decl.methods {
+			name := method.name.lexeme
+			if own[name] {
+				continue
+			}
+			if other, ok := providers[name]; ok {
+				r.error(trait.name, "Method '"+name+"' is ambiguous between traits "+other+" and "+trait.name.lexeme+"; define it in the class.")
+				continue
+			}
+			providers[name] = trait.name.lexeme
+		}
+	}
+}
+
 func (r *Resolver) resolveFunction(stmt *FunctionStmt, ftype FunctionType) {
 	enclosingFn := r.currentFn
+	enclosingGenerator := r.inGenerator
 	r.currentFn = ftype
+	r.inGenerator = stmt.isGenerator
 
 	r.beginScope()
-	for _, param := range stmt.params {
+	for i, param := range stmt.params {
+		if stmt.defaults[i] != nil {
+			r.resolveExpr(stmt.defaults[i])
+		}
 		r.declare(param)
 		r.define(param)
 	}
+	if stmt.rest != nil {
+		r.declare(stmt.rest)
+		r.define(stmt.rest)
+	}
 	r.Resolve(stmt.body)
 	r.endScope()
 
 	r.currentFn = enclosingFn
+	r.inGenerator = enclosingGenerator
 }
 
 // ================================================================================
@@ -92,10 +169,18 @@ func (r *Resolver) visitBlockStmt(stmt *BlockStmt) (StmtReturn, error) {
 
 func (r *Resolver) visitClassStmt(stmt *ClassStmt) (StmtReturn, error) {
 	enclosingClass := r.currentClass
+	enclosingStatic := r.inStatic
+	enclosingDeclared := r.declared
 	r.currentClass = ClassClass
+	r.inStatic = false
 
 	r.declare(stmt.name)
 	r.define(stmt.name)
+	r.classes[stmt.name.lexeme] = stmt
+	if stmt.record != nil {
+		r.checkRecordMethods(stmt)
+	}
+	r.declared = r.declaredFields(stmt)
 
 	if stmt.superclass != nil && stmt.name.lexeme == stmt.superclass.name.lexeme {
 		r.error(stmt.superclass.name, "A class can't inherit from itself")
@@ -106,25 +191,113 @@ func (r *Resolver) visitClassStmt(stmt *ClassStmt) (StmtReturn, error) {
 		r.resolveExpr(stmt.superclass)
 	}
 
+	for _, trait := range stmt.traits {
+		r.resolveExpr(trait)
+	}
+	r.checkTraitConflicts(stmt)
+
+	for _, iface := range stmt.interfaces {
+		r.resolveExpr(iface)
+	}
+	for _, method := range stmt.abstracts {
+		if method.name.lexeme == "init" {
+			r.error(method.name, "An initializer can't be abstract.")
+		}
+	}
+
 	if stmt.superclass != nil {
 		r.beginScope()
 		r.scopes.Peek()["super"] = true
 	}
 
+	r.inStatic = true
+	for _, method := range stmt.classMethods {
+		r.resolveFunction(method, FunctionMethod)
+	}
+	r.inStatic = false
+
 	r.beginScope()
 	r.scopes.Peek()["this"] = true
+	for _, field := range stmt.fields {
+		if field.initializer != nil {
+			r.resolveExpr(field.initializer)
+		}
+	}
 	for _, method := range stmt.methods {
 		declaration := FunctionMethod
 		if method.name.lexeme == "init" {
+			if method.isGetter {
+				r.error(method.name, "An initializer can't be a getter.")
+			}
 			declaration = FunctionInitializer
 		}
 		r.resolveFunction(method, declaration)
 	}
+	for _, setter := range stmt.setters {
+		r.resolveFunction(setter, FunctionMethod)
+	}
 	r.endScope()
 	if stmt.superclass != nil {
 		r.endScope()
 	}
+
+	r.inStatic = true
+	for _, field := range stmt.classFields {
+		if field.initializer != nil {
+			r.resolveExpr(field.initializer)
+		}
+	}
+	r.inStatic = enclosingStatic
 	r.currentClass = enclosingClass
+	r.declared = enclosingDeclared
+	return StmtReturn{}, nil
+}
+
+// checkRecordMethods reports methods of a record that clash with the
+// generated initializer and with() method, which come first.
+func (r *Resolver) checkRecordMethods(stmt *ClassStmt) {
+	for _, method := range stmt.methods[2:] {
+		if method.name.lexeme == "init" || method.name.lexeme == "with" {
+			r.error(method.name, "A record can't redefine '"+method.name.lexeme+"'.")
+		}
+	}
+}
+
+// declaredFields collects the fields and setters of a class that declares
+// its fields, along with those of its superclasses. Classes without field
+// declarations, or whose superclasses can't be found, aren't checked.
+func (r *Resolver) declaredFields(stmt *ClassStmt) map[string]bool {
+	if len(stmt.fields) == 0 {
+		return nil
+	}
+	declared := make(map[string]bool)
+	for class := stmt; class != nil; {
+		for _, field := range class.fields {
+			declared[field.name.lexeme] = true
+		}
+		for _, setter := range class.setters {
+			declared[setter.name.lexeme] = true
+		}
+		if class.superclass == nil {
+			break
+		}
+		superclass, ok := r.classes[class.superclass.name.lexeme]
+		if !ok || superclass == class {
+			return nil
+		}
+		class = superclass
+	}
+	return declared
+}
+
+func (r *Resolver) visitDestructureStmt(stmt *DestructureStmt) (StmtReturn, error) {
+	for _, target := range stmt.pattern.targets {
+		r.declare(target.name)
+	}
+	r.resolveExpr(stmt.initializer)
+	for _, target := range stmt.pattern.targets {
+		r.define(target.name)
+	}
 	return StmtReturn{}, nil
 }
 
@@ -133,6 +306,16 @@ func (r *Resolver) visitExpressionStmt(stmt *ExpressionStmt) (StmtReturn, error)
 	return StmtReturn{}, nil
 }
 
+func (r *Resolver) visitForInStmt(stmt *ForInStmt) (StmtReturn, error) {
+	r.resolveExpr(stmt.iterable)
+	r.beginScope()
+	r.declare(stmt.name)
+	r.define(stmt.name)
+	r.resolveStmt(stmt.body)
+	r.endScope()
+	return StmtReturn{}, nil
+}
+
 func (r *Resolver) visitFunctionStmt(stmt *FunctionStmt) (StmtReturn, error) {
 	r.declare(stmt.name)
 	r.define(stmt.name)
@@ -163,17 +346,111 @@ func (r *Resolver) visitReturnStmt(stmt *ReturnStmt) (StmtReturn, error) {
 		if r.currentFn == FunctionInitializer {
 			r.error(stmt.keyword, "Cannot return a value from an initializer.")
 		}
+		if r.inGenerator {
+			r.error(stmt.keyword, "Cannot return a value from a generator.")
+		}
 		r.resolveExpr(stmt.value)
 	}
 	return StmtReturn{}, nil
 }
 
+func (r *Resolver) visitEnumStmt(stmt *EnumStmt) (StmtReturn, error) {
+	r.declare(stmt.name)
+	r.define(stmt.name)
+	seen := make(map[string]bool)
+	for k, member := range stmt.members {
+		if seen[member.lexeme] {
+			r.error(member, "Member '"+member.lexeme+"' is already declared in this enum.")
+		}
+		seen[member.lexeme] = true
+		if stmt.values[k] != nil {
+			r.resolveExpr(stmt.values[k])
+		}
+	}
+	return StmtReturn{}, nil
+}
+
+func (r *Resolver) visitImportStmt(stmt *ImportStmt) (StmtReturn, error) {
+	if !r.scopes.IsEmpty() {
+		r.error(stmt.keyword, "Imports must be at the top level.")
+	}
+	r.declare(stmt.name)
+	r.define(stmt.name)
+	return StmtReturn{}, nil
+}
+
+func (r *Resolver) visitExportStmt(stmt *ExportStmt) (StmtReturn, error) {
+	if !r.scopes.IsEmpty() {
+		r.error(stmt.keyword, "Exports must be at the top level.")
+	}
+	r.resolveStmt(stmt.declaration)
+	return StmtReturn{}, nil
+}
+
+func (r *Resolver) visitInterfaceStmt(stmt *InterfaceStmt) (StmtReturn, error) {
+	r.declare(stmt.name)
+	r.define(stmt.name)
+	seen := make(map[string]bool)
+	for _, method := range stmt.methods {
+		if seen[method.name.lexeme] {
+			r.error(method.name, "Method '"+method.name.lexeme+"' is already declared in this interface.")
+		}
+		seen[method.name.lexeme] = true
+	}
+	return StmtReturn{}, nil
+}
+
+func (r *Resolver) visitTraitStmt(stmt *TraitStmt) (StmtReturn, error) {
+	enclosingClass := r.currentClass
+	enclosingStatic := r.inStatic
+	r.currentClass = ClassTrait
+	r.inStatic = false
+
+	r.declare(stmt.name)
+	r.define(stmt.name)
+	r.traits[stmt.name.lexeme] = stmt
+
+	r.beginScope()
+	r.scopes.Peek()["this"] = true
+	for _, method := range stmt.methods {
+		if method.name.lexeme == "init" {
+			r.error(method.name, "A trait can't define an initializer.")
+		}
+		r.resolveFunction(method, FunctionMethod)
+	}
+	r.endScope()
+
+	r.inStatic = enclosingStatic
+	r.currentClass = enclosingClass
+	return StmtReturn{}, nil
+}
+
 func (r *Resolver) visitVarStmt(stmt *VarStmt) (StmtReturn, error) {
 	r.declare(stmt.name)
 	if stmt.initializer != nil {
 		r.resolveExpr(stmt.initializer)
 	}
 	r.define(stmt.name)
+	if stmt.isConst {
+		if r.scopes.IsEmpty() {
+			r.globalConsts[stmt.name.lexeme] = true
+		} else {
+			r.constants.Peek()[stmt.name.lexeme] = true
+		}
+	}
+	return StmtReturn{}, nil
+}
+
+func (r *Resolver) visitYieldStmt(stmt *YieldStmt) (StmtReturn, error) {
+	if r.currentFn == FunctionNone {
+		r.error(stmt.keyword, "Cannot yield from top-level code.")
+	}
+	if r.currentFn == FunctionInitializer {
+		r.error(stmt.keyword, "Cannot yield from an initializer.")
+	}
+	if stmt.value != nil {
+		r.resolveExpr(stmt.value)
+	}
 	return StmtReturn{}, nil
 }
 
@@ -189,6 +466,7 @@ func (r *Resolver) visitWhileStmt(stmt *WhileStmt) (StmtReturn, error) {
 
 func (r *Resolver) visitAssignExpr(expr *AssignExpr) (interface{}, error) {
 	r.resolveExpr(expr.value)
+	r.checkAssignable(expr.name)
 	r.resolveLocal(expr, expr.name)
 	return nil, nil
 }
@@ -204,11 +482,29 @@ func (r *Resolver) visitCallExpr(expr *CallExpr) (interface{}, error) {
 	for _, arg := range expr.args {
 		r.resolveExpr(arg)
 	}
+	for _, kwarg := range expr.kwargs {
+		r.resolveExpr(kwarg.value)
+	}
+	return nil, nil
+}
+
+func (r *Resolver) visitClassOfExpr(expr *ClassOfExpr) (interface{}, error) {
+	r.resolveExpr(expr.object)
+	return nil, nil
+}
+
+func (r *Resolver) visitDestructureExpr(expr *DestructureExpr) (interface{}, error) {
+	r.resolveExpr(expr.value)
+	for _, target := range expr.pattern.targets {
+		r.checkAssignable(target.name)
+		r.resolveLocal(target, target.name)
+	}
 	return nil, nil
 }
 
 func (r *Resolver) visitGetExpr(expr *GetExpr) (interface{}, error) {
 	r.resolveExpr(expr.object)
+	r.checkPrivate(expr.object, expr.name)
 	return nil, nil
 }
 
@@ -217,6 +513,36 @@ func (r *Resolver) visitGroupingExpr(expr *GroupingExpr) (interface{}, error) {
 	return nil, nil
 }
 
+func (r *Resolver) visitIndexExpr(expr *IndexExpr) (interface{}, error) {
+	r.resolveExpr(expr.object)
+	r.resolveExpr(expr.index)
+	return nil, nil
+}
+
+func (r *Resolver) visitIndexSetExpr(expr *IndexSetExpr) (interface{}, error) {
+	r.resolveExpr(expr.value)
+	r.resolveExpr(expr.object)
+	r.resolveExpr(expr.index)
+	return nil, nil
+}
+
+func (r *Resolver) visitListExpr(expr *ListExpr) (interface{}, error) {
+	for _, element := range expr.elements {
+		r.resolveExpr(element)
+	}
+	return nil, nil
+}
+
+func (r *Resolver) visitMapExpr(expr *MapExpr) (interface{}, error) {
+	for i, key := range expr.keys {
+		r.resolveExpr(key)
+		if expr.values[i] != nil {
+			r.resolveExpr(expr.values[i])
+		}
+	}
+	return nil, nil
+}
+
 func (r *Resolver) visitLiteralExpr(expr *LiteralExpr) (interface{}, error) {
 	return nil, nil
 }
@@ -227,18 +553,74 @@ func (r *Resolver) visitLogicalExpr(expr *LogicalExpr) (interface{}, error) {
 	return nil, nil
 }
 
+func (r *Resolver) visitOptionalChainExpr(expr *OptionalChainExpr) (interface{}, error) {
+	r.resolveExpr(expr.expr)
+	return nil, nil
+}
+
+func (r *Resolver) visitRangeExpr(expr *RangeExpr) (interface{}, error) {
+	r.resolveExpr(expr.start)
+	r.resolveExpr(expr.stop)
+	if expr.step != nil {
+		r.resolveExpr(expr.step)
+	}
+	return nil, nil
+}
+
 func (r *Resolver) visitSetExpr(expr *SetExpr) (interface{}, error) {
 	r.resolveExpr(expr.value)
 	r.resolveExpr(expr.object)
+	r.checkPrivate(expr.object, expr.name)
+	if _, ok := expr.object.(*ThisExpr); ok && r.declared != nil && !r.declared[expr.name.lexeme] {
+		r.warn(expr.name, "Assignment to undeclared field '"+expr.name.lexeme+"'.")
+	}
+	return nil, nil
+}
+
+// checkPrivate reports accesses to private members that are not made through
+// 'this' inside a class body.
+func (r *Resolver) checkPrivate(object Expr, name *Token) {
+	if !isPrivate(name.lexeme) {
+		return
+	}
+	switch {
+	case r.currentClass == ClassNone:
+		r.error(name, "Cannot access private member '"+name.lexeme+"' outside of a class.")
+	case r.currentClass == ClassTrait:
+		r.error(name, "Cannot access private member '"+name.lexeme+"' in a trait.")
+	default:
+		if _, ok := object.(*ThisExpr); !ok {
+			r.error(name, "Private member '"+name.lexeme+"' can only be accessed through 'this'.")
+		}
+	}
+}
+
+func (r *Resolver) visitSliceExpr(expr *SliceExpr) (interface{}, error) {
+	r.resolveExpr(expr.object)
+	for _, bound := range []Expr{expr.start, expr.stop, expr.step} {
+		if bound != nil {
+			r.resolveExpr(bound)
+		}
+	}
+	return nil, nil
+}
+
+func (r *Resolver) visitSpreadExpr(expr *SpreadExpr) (interface{}, error) {
+	r.resolveExpr(expr.expr)
 	return nil, nil
 }
 
 func (r *Resolver) visitSuperExpr(expr *SuperExpr) (interface{}, error) {
 	if r.currentClass == ClassNone {
 		r.error(expr.keyword, "can't use 'super' outside of a class")
+	} else if r.inStatic {
+		r.error(expr.keyword, "can't use 'super' in a static method or class field")
+	} else if r.currentClass == ClassTrait {
+		r.error(expr.keyword, "can't use 'super' in a trait")
 	} else if r.currentClass != ClassSubclass {
-		r.error(expr.keyword, "can't use 'super' in a class with no subclass")
+		r.error(expr.keyword, "can't use 'super' in a class with no superclass")
 	}
+	r.checkPrivate(expr, expr.method)
 	r.resolveLocal(expr, expr.keyword)
 	return nil, nil
 }
@@ -248,11 +630,22 @@ func (r *Resolver) visitThisExpr(expr *ThisExpr) (interface{}, error) {
 		r.error(expr.keyword, "Cannot use 'this' outside of a class.")
 		return nil, nil
 	}
+	if r.inStatic {
+		r.error(expr.keyword, "Cannot use 'this' in a static method or class field.")
+		return nil, nil
+	}
 
 	r.resolveLocal(expr, expr.keyword)
 	return nil, nil
 }
 
+func (r *Resolver) visitTupleExpr(expr *TupleExpr) (interface{}, error) {
+	for _, element := range expr.elements {
+		r.resolveExpr(element)
+	}
+	return nil, nil
+}
+
 func (r *Resolver) visitUnaryExpr(expr *UnaryExpr) (interface{}, error) {
 	r.resolveExpr(expr.right)
 	return nil, nil
@@ -274,14 +667,21 @@ func (r *Resolver) visitVariableExpr(expr *VariableExpr) (interface{}, error) {
 
 func (r *Resolver) beginScope() {
 	r.scopes.Push(make(map[string]bool))
+	r.constants.Push(make(map[string]bool))
 }
 
 func (r *Resolver) endScope() {
 	r.scopes.Pop()
+	r.constants.Pop()
 }
 
+// declare adds name to the innermost scope. Globals aren't tracked, except
+// that a global constant can't be declared again by any statement.
 func (r *Resolver) declare(name *Token) {
 	if r.scopes.IsEmpty() {
+		if r.globalConsts[name.lexeme] {
+			r.error(name, "Cannot redeclare constant '"+name.lexeme+"'.")
+		}
 		return
 	}
 	scope := r.scopes.Peek()
@@ -299,6 +699,12 @@ func (r *Resolver) define(name *Token) {
 }
 
 func (r *Resolver) error(token *Token, msg string) {
-	fmt.Println("[line", token.line, "] Error", msg)
+	fmt.Println("["+token.location(), "] Error", msg)
 	r.hadErr = true
 }
+
+// warn reports a problem that doesn't stop the program. Warnings go to
+// stderr, like the scanner's errors, so they don't mix with its output.
+func (r *Resolver) warn(token *Token, msg string) {
+	log.Println("["+token.location(), "] Warning", msg)
+}
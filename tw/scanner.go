@@ -6,18 +6,35 @@ import (
 )
 
 var keywords = map[string]TokenType{
-	"for":    FOR,
-	"fun":    FUN,
-	"if":     IF,
-	"nil":    NIL,
-	"or":     OR,
-	"print":  PRINT,
-	"return": RETURN,
-	"super":  SUPER,
-	"this":   THIS,
-	"true":   TRUE,
-	"var":    VAR,
-	"while":  WHILE,
+	"abstract":   ABSTRACT,
+	"and":        AND,
+	"class":      CLASS,
+	"const":      CONST,
+	"else":       ELSE,
+	"enum":       ENUM,
+	"export":     EXPORT,
+	"false":      FALSE,
+	"for":        FOR,
+	"fun":        FUN,
+	"if":         IF,
+	"implements": IMPLEMENTS,
+	"import":     IMPORT,
+	"in":         IN,
+	"interface":  INTERFACE,
+	"is":         IS,
+	"nil":        NIL,
+	"or":         OR,
+	"print":      PRINT,
+	"record":     RECORD,
+	"return":     RETURN,
+	"super":      SUPER,
+	"this":       THIS,
+	"trait":      TRAIT,
+	"true":       TRUE,
+	"var":        VAR,
+	"while":      WHILE,
+	"with":       WITH,
+	"yield":      YIELD,
 }
 
 type Scanner struct {
@@ -27,12 +44,16 @@ type Scanner struct {
 	start    int
 	current  int
 	line     int
+	file     string
 	hadErr   bool
 }
 
-func NewScanner(src string) *Scanner {
+// NewScanner scans src, read from file. The file is empty for the main
+// script and is recorded on every token for diagnostics.
+func NewScanner(src string, file string) *Scanner {
 	return &Scanner{
 		source:   src,
+		file:     file,
 		tokens:   make([]*Token, 0),
 		keywords: keywords,
 		line:     1,
@@ -44,7 +65,9 @@ func (s *Scanner) Scan() ([]*Token, bool) {
 		s.start = s.current
 		s.scanToken()
 	}
-	s.tokens = append(s.tokens, NewToken(EOF, "", nil, s.line))
+	eof := NewToken(EOF, "", nil, s.line)
+	eof.file = s.file
+	s.tokens = append(s.tokens, eof)
 	return s.tokens, s.hadErr
 }
 
@@ -59,10 +82,31 @@ func (s *Scanner) scanToken() {
 		s.addToken(LEFT_BRACE, nil)
 	case "}":
 		s.addToken(RIGHT_BRACE, nil)
+	case "[":
+		s.addToken(LEFT_BRACKET, nil)
+	case "]":
+		s.addToken(RIGHT_BRACKET, nil)
 	case ",":
 		s.addToken(COMMA, nil)
+	case ":":
+		s.addToken(COLON, nil)
 	case ".":
-		s.addToken(DOT, nil)
+		if s.peek() == "." && s.peekNext() == "." {
+			s.current += 2
+			s.addToken(ELLIPSIS, nil)
+		} else if s.match(".") {
+			s.matchElse("=", DOT_DOT_EQUAL, DOT_DOT)
+		} else {
+			s.addToken(DOT, nil)
+		}
+	case "?":
+		if s.match(".") {
+			s.addToken(QUESTION_DOT, nil)
+		} else if s.match("?") {
+			s.addToken(QUESTION_QUESTION, nil)
+		} else {
+			s.error(s.line, "Unexpected character.")
+		}
 	case "-":
 		s.addToken(MINUS, nil)
 	case "+":
@@ -92,6 +136,12 @@ func (s *Scanner) scanToken() {
 		s.line++
 	case "\"":
 		s.string()
+	case "#":
+		if isAlpha(s.peek()) {
+			s.identifier()
+		} else {
+			s.error(s.line, "Unexpected character.")
+		}
 	default:
 		if isDigit(c) {
 			s.number()
@@ -104,7 +154,9 @@ func (s *Scanner) scanToken() {
 }
 func (s *Scanner) addToken(tt TokenType, literal interface{}) {
 	text := s.source[s.start:s.current]
-	s.tokens = append(s.tokens, NewToken(tt, text, literal, s.line))
+	token := NewToken(tt, text, literal, s.line)
+	token.file = s.file
+	s.tokens = append(s.tokens, token)
 }
 
 // ================================================================================
@@ -208,6 +260,6 @@ func isDigit(c string) bool {
 }
 
 func (s *Scanner) error(line int, message string) {
-	log.Printf("[line %d] Error: %s", line, message)
+	log.Printf("[%s] Error: %s", location(s.file, line), message)
 	s.hadErr = true
 }
@@ -0,0 +1,119 @@
+package tw
+
+import "fmt"
+
+// Sliceable is a sequence that supports `value[start:stop:step]`.
+type Sliceable interface {
+	Slice(start, stop, step interface{}) (interface{}, error)
+}
+
+// sliceIndices returns the positions selected by a slice of a sequence with
+// the given length. Omitted bounds are nil and negative bounds count back
+// from the end, following Python's slicing rules.
+func sliceIndices(length int, start, stop, step interface{}) ([]int, error) {
+	by := 1
+	if step != nil {
+		n, ok := step.(int)
+		if !ok {
+			return nil, fmt.Errorf("Slice step must be a number.")
+		}
+		if n == 0 {
+			return nil, fmt.Errorf("Slice step can't be zero.")
+		}
+		by = n
+	}
+
+	lower, upper := 0, length
+	if by < 0 {
+		lower, upper = -1, length-1
+	}
+	clamp := func(bound interface{}, fallback int) (int, error) {
+		if bound == nil {
+			return fallback, nil
+		}
+		n, ok := bound.(int)
+		if !ok {
+			return 0, fmt.Errorf("Slice indices must be numbers.")
+		}
+		if n < 0 {
+			n += length
+		}
+		if n < lower {
+			n = lower
+		}
+		if n > upper {
+			n = upper
+		}
+		return n, nil
+	}
+
+	var from, to int
+	var err error
+	if by > 0 {
+		from, err = clamp(start, 0)
+		if err == nil {
+			to, err = clamp(stop, length)
+		}
+	} else {
+		from, err = clamp(start, length-1)
+		if err == nil {
+			to, err = clamp(stop, -1)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	indices := make([]int, 0)
+	for i := from; (by > 0 && i < to) || (by < 0 && i > to); i += by {
+		indices = append(indices, i)
+	}
+	return indices, nil
+}
+
+func (l *List) Slice(start, stop, step interface{}) (interface{}, error) {
+	indices, err := sliceIndices(len(l.elements), start, stop, step)
+	if err != nil {
+		return nil, err
+	}
+	elements := make([]interface{}, len(indices))
+	for i, index := range indices {
+		elements[i] = l.elements[index]
+	}
+	return NewList(elements), nil
+}
+
+func (t *Tuple) Slice(start, stop, step interface{}) (interface{}, error) {
+	indices, err := sliceIndices(len(t.elements), start, stop, step)
+	if err != nil {
+		return nil, err
+	}
+	elements := make([]interface{}, len(indices))
+	for i, index := range indices {
+		elements[i] = t.elements[index]
+	}
+	return NewTuple(elements), nil
+}
+
+// indexString returns the rune of s at index as a string.
+func indexString(s string, index interface{}) (interface{}, error) {
+	runes := []rune(s)
+	i, err := sequenceIndex("String", index, len(runes))
+	if err != nil {
+		return nil, err
+	}
+	return string(runes[i]), nil
+}
+
+func sliceString(s string, start, stop, step interface{}) (interface{}, error) {
+	runes := []rune(s)
+	indices, err := sliceIndices(len(runes), start, stop, step)
+	if err != nil {
+		return nil, err
+	}
+	sliced := make([]rune, len(indices))
+	for i, index := range indices {
+		sliced[i] = runes[index]
+	}
+	return string(sliced), nil
+}
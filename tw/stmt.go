@@ -12,13 +12,21 @@ type Stmt interface {
 type StmtVisitor interface {
 	visitBlockStmt(stmt *BlockStmt) (StmtReturn, error)
 	visitClassStmt(stmt *ClassStmt) (StmtReturn, error)
+	visitDestructureStmt(stmt *DestructureStmt) (StmtReturn, error)
+	visitExportStmt(stmt *ExportStmt) (StmtReturn, error)
 	visitExpressionStmt(stmt *ExpressionStmt) (StmtReturn, error)
+	visitForInStmt(stmt *ForInStmt) (StmtReturn, error)
 	visitFunctionStmt(stmt *FunctionStmt) (StmtReturn, error)
+	visitEnumStmt(stmt *EnumStmt) (StmtReturn, error)
 	visitIfStmt(stmt *IfStmt) (StmtReturn, error)
+	visitImportStmt(stmt *ImportStmt) (StmtReturn, error)
+	visitInterfaceStmt(stmt *InterfaceStmt) (StmtReturn, error)
 	visitPrintStmt(stmt *PrintStmt) (StmtReturn, error)
 	visitReturnStmt(stmt *ReturnStmt) (StmtReturn, error)
+	visitTraitStmt(stmt *TraitStmt) (StmtReturn, error)
 	visitVarStmt(stmt *VarStmt) (StmtReturn, error)
 	visitWhileStmt(stmt *WhileStmt) (StmtReturn, error)
+	visitYieldStmt(stmt *YieldStmt) (StmtReturn, error)
 }
 
 // ================================================================================
@@ -38,15 +46,47 @@ func (stmt *BlockStmt) Accept(v StmtVisitor) (StmtReturn, error) {
 // ================================================================================
 
 type ClassStmt struct {
-	name       *Token
-	superclass *VariableExpr
-	methods    []*FunctionStmt
+	name         *Token
+	superclass   *VariableExpr
+	traits       []*VariableExpr
+	interfaces   []*VariableExpr
+	methods      []*FunctionStmt
+	abstracts    []*FunctionStmt
+	setters      []*FunctionStmt
+	classMethods []*FunctionStmt
+	classFields  []*VarStmt
+	fields       []*VarStmt
+	// record lists the components of a record declaration, and is nil for
+	// ordinary classes.
+	record []*Token
 }
 
 func (stmt *ClassStmt) Accept(v StmtVisitor) (StmtReturn, error) {
 	return v.visitClassStmt(stmt)
 }
 
+func (stmt *ClassStmt) definesMethod(name string) bool {
+	for _, method := range stmt.methods {
+		if method.name.lexeme == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ================================================================================
+// ### DESTRUCTURE
+// ================================================================================
+
+type DestructureStmt struct {
+	pattern     *Pattern
+	initializer Expr
+}
+
+func (stmt *DestructureStmt) Accept(v StmtVisitor) (StmtReturn, error) {
+	return v.visitDestructureStmt(stmt)
+}
+
 // ================================================================================
 // ### EXPRESSION
 // ================================================================================
@@ -59,14 +99,34 @@ func (stmt *ExpressionStmt) Accept(v StmtVisitor) (StmtReturn, error) {
 	return v.visitExpressionStmt(stmt)
 }
 
+// ================================================================================
+// ### FOR IN
+// ================================================================================
+
+type ForInStmt struct {
+	keyword  *Token
+	name     *Token
+	iterable Expr
+	body     Stmt
+}
+
+func (stmt *ForInStmt) Accept(v StmtVisitor) (StmtReturn, error) {
+	return v.visitForInStmt(stmt)
+}
+
 // ================================================================================
 // ### FUNCTION
 // ================================================================================
 
 type FunctionStmt struct {
-	name   *Token
-	params []*Token
-	body   []Stmt
+	name        *Token
+	params      []*Token
+	defaults    []Expr
+	rest        *Token
+	body        []Stmt
+	isGenerator bool
+	isGetter    bool
+	isAbstract  bool
 }
 
 func (stmt *FunctionStmt) Accept(v StmtVisitor) (StmtReturn, error) {
@@ -87,6 +147,65 @@ func (stmt *IfStmt) Accept(v StmtVisitor) (StmtReturn, error) {
 	return v.visitIfStmt(stmt)
 }
 
+// ================================================================================
+// ### ENUM
+// ================================================================================
+
+// EnumStmt declares an enum. values is parallel to members and holds nil
+// for members without an associated value.
+type EnumStmt struct {
+	name    *Token
+	members []*Token
+	values  []Expr
+}
+
+func (stmt *EnumStmt) Accept(v StmtVisitor) (StmtReturn, error) {
+	return v.visitEnumStmt(stmt)
+}
+
+// ================================================================================
+// ### EXPORT
+// ================================================================================
+
+// ExportStmt wraps a top-level declaration whose name is exported from the
+// module.
+type ExportStmt struct {
+	keyword     *Token
+	name        *Token
+	declaration Stmt
+}
+
+func (stmt *ExportStmt) Accept(v StmtVisitor) (StmtReturn, error) {
+	return v.visitExportStmt(stmt)
+}
+
+// ================================================================================
+// ### IMPORT
+// ================================================================================
+
+type ImportStmt struct {
+	keyword *Token
+	path    *Token
+	name    *Token
+}
+
+func (stmt *ImportStmt) Accept(v StmtVisitor) (StmtReturn, error) {
+	return v.visitImportStmt(stmt)
+}
+
+// ================================================================================
+// ### INTERFACE
+// ================================================================================
+
+type InterfaceStmt struct {
+	name    *Token
+	methods []*FunctionStmt
+}
+
+func (stmt *InterfaceStmt) Accept(v StmtVisitor) (StmtReturn, error) {
+	return v.visitInterfaceStmt(stmt)
+}
+
 // ================================================================================
 // ### PRINT
 // ================================================================================
@@ -112,6 +231,19 @@ func (stmt *ReturnStmt) Accept(v StmtVisitor) (StmtReturn, error) {
 	return v.visitReturnStmt(stmt)
 }
 
+// ================================================================================
+// ### TRAIT
+// ================================================================================
+
+type TraitStmt struct {
+	name    *Token
+	methods []*FunctionStmt
+}
+
+func (stmt *TraitStmt) Accept(v StmtVisitor) (StmtReturn, error) {
+	return v.visitTraitStmt(stmt)
+}
+
 // ================================================================================
 // ### VAR
 // ================================================================================
@@ -119,6 +251,7 @@ func (stmt *ReturnStmt) Accept(v StmtVisitor) (StmtReturn, error) {
 type VarStmt struct {
 	name        *Token
 	initializer Expr
+	isConst     bool
 }
 
 func (stmt *VarStmt) Accept(v StmtVisitor) (StmtReturn, error) {
@@ -137,3 +270,16 @@ type WhileStmt struct {
 func (stmt *WhileStmt) Accept(v StmtVisitor) (StmtReturn, error) {
 	return v.visitWhileStmt(stmt)
 }
+
+// ================================================================================
+// ### YIELD
+// ================================================================================
+
+type YieldStmt struct {
+	keyword *Token
+	value   Expr
+}
+
+func (stmt *YieldStmt) Accept(v StmtVisitor) (StmtReturn, error) {
+	return v.visitYieldStmt(stmt)
+}
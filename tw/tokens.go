@@ -6,27 +6,35 @@ type TokenType string
 
 const (
 	// Single-character tokens
-	LEFT_PAREN  TokenType = "LEFT_PAREN"
-	RIGHT_PAREN TokenType = "RIGHT_PAREN"
-	LEFT_BRACE  TokenType = "LEFT_BRACE"
-	RIGHT_BRACE TokenType = "RIGHT_BRACE"
-	COMMA       TokenType = "COMMA"
-	DOT         TokenType = "DOT"
-	MINUS       TokenType = "MINUS"
-	PLUS        TokenType = "PLUS"
-	SEMICOLON   TokenType = "SEMICOLON"
-	SLASH       TokenType = "SLASH"
-	STAR        TokenType = "STAR"
+	LEFT_PAREN    TokenType = "LEFT_PAREN"
+	RIGHT_PAREN   TokenType = "RIGHT_PAREN"
+	LEFT_BRACE    TokenType = "LEFT_BRACE"
+	RIGHT_BRACE   TokenType = "RIGHT_BRACE"
+	LEFT_BRACKET  TokenType = "LEFT_BRACKET"
+	RIGHT_BRACKET TokenType = "RIGHT_BRACKET"
+	COMMA         TokenType = "COMMA"
+	COLON         TokenType = "COLON"
+	DOT           TokenType = "DOT"
+	MINUS         TokenType = "MINUS"
+	PLUS          TokenType = "PLUS"
+	SEMICOLON     TokenType = "SEMICOLON"
+	SLASH         TokenType = "SLASH"
+	STAR          TokenType = "STAR"
 
 	// One or two character tokens
-	BANG          TokenType = "BANG"
-	BANG_EQUAL    TokenType = "BANG_EQUAL"
-	EQUAL         TokenType = "EQUAL"
-	EQUAL_EQUAL   TokenType = "EQUAL_EQUAL"
-	GREATER       TokenType = "GREATER"
-	GREATER_EQUAL TokenType = "GREATER_EQUAL"
-	LESS          TokenType = "LESS"
-	LESS_EQUAL    TokenType = "LESS_EQUAL"
+	BANG              TokenType = "BANG"
+	BANG_EQUAL        TokenType = "BANG_EQUAL"
+	EQUAL             TokenType = "EQUAL"
+	EQUAL_EQUAL       TokenType = "EQUAL_EQUAL"
+	GREATER           TokenType = "GREATER"
+	GREATER_EQUAL     TokenType = "GREATER_EQUAL"
+	LESS              TokenType = "LESS"
+	LESS_EQUAL        TokenType = "LESS_EQUAL"
+	DOT_DOT           TokenType = "DOT_DOT"
+	DOT_DOT_EQUAL     TokenType = "DOT_DOT_EQUAL"
+	ELLIPSIS          TokenType = "ELLIPSIS"
+	QUESTION_DOT      TokenType = "QUESTION_DOT"
+	QUESTION_QUESTION TokenType = "QUESTION_QUESTION"
 
 	// Literals
 	IDENTIFIER TokenType = "IDENTIFIER"
@@ -34,22 +42,35 @@ const (
 	NUMBER     TokenType = "NUMBER"
 
 	// Keywords
-	AND    TokenType = "AND"
-	CLASS  TokenType = "CLASS"
-	ELSE   TokenType = "ELSE"
-	FALSE  TokenType = "FALSE"
-	FUN    TokenType = "FUN"
-	FOR    TokenType = "FOR"
-	IF     TokenType = "IF"
-	NIL    TokenType = "NIL"
-	OR     TokenType = "OR"
-	PRINT  TokenType = "PRINT"
-	RETURN TokenType = "RETURN"
-	SUPER  TokenType = "SUPER"
-	THIS   TokenType = "THIS"
-	TRUE   TokenType = "TRUE"
-	VAR    TokenType = "VAR"
-	WHILE  TokenType = "WHILE"
+	ABSTRACT   TokenType = "ABSTRACT"
+	AND        TokenType = "AND"
+	CLASS      TokenType = "CLASS"
+	CONST      TokenType = "CONST"
+	ELSE       TokenType = "ELSE"
+	ENUM       TokenType = "ENUM"
+	EXPORT     TokenType = "EXPORT"
+	FALSE      TokenType = "FALSE"
+	FUN        TokenType = "FUN"
+	FOR        TokenType = "FOR"
+	IF         TokenType = "IF"
+	IMPLEMENTS TokenType = "IMPLEMENTS"
+	IMPORT     TokenType = "IMPORT"
+	IN         TokenType = "IN"
+	INTERFACE  TokenType = "INTERFACE"
+	IS         TokenType = "IS"
+	NIL        TokenType = "NIL"
+	OR         TokenType = "OR"
+	PRINT      TokenType = "PRINT"
+	RECORD     TokenType = "RECORD"
+	RETURN     TokenType = "RETURN"
+	SUPER      TokenType = "SUPER"
+	THIS       TokenType = "THIS"
+	TRAIT      TokenType = "TRAIT"
+	TRUE       TokenType = "TRUE"
+	VAR        TokenType = "VAR"
+	WHILE      TokenType = "WHILE"
+	WITH       TokenType = "WITH"
+	YIELD      TokenType = "YIELD"
 
 	EOF TokenType = "EOF"
 )
@@ -59,6 +80,9 @@ type Token struct {
 	lexeme  string
 	literal interface{}
 	line    int
+	// file is the path of the module the token was scanned from. It is empty
+	// for the main script.
+	file string
 }
 
 func NewToken(ttype TokenType, lexeme string, literal interface{}, line int) *Token {
@@ -73,3 +97,15 @@ func NewToken(ttype TokenType, lexeme string, literal interface{}, line int) *To
 func (t *Token) String() string {
 	return fmt.Sprintf("%s %s %v", t.ttype, t.lexeme, t.literal)
 }
+
+// location describes where the token is for diagnostics.
+func (t *Token) location() string {
+	return location(t.file, t.line)
+}
+
+func location(file string, line int) string {
+	if file == "" {
+		return fmt.Sprintf("line %d", line)
+	}
+	return fmt.Sprintf("%s, line %d", file, line)
+}
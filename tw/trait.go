@@ -0,0 +1,18 @@
+package tw
+
+// Trait is a named bundle of methods that classes mix in with `with`.
+type Trait struct {
+	name    string
+	methods map[string]*Function
+}
+
+func NewTrait(name string, methods map[string]*Function) *Trait {
+	return &Trait{
+		name:    name,
+		methods: methods,
+	}
+}
+
+func (t *Trait) String() string {
+	return "<trait " + t.name + ">"
+}
@@ -0,0 +1,56 @@
+package tw
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tuple is an immutable sequence, produced by `return a, b;` and `(a, b)`.
+type Tuple struct {
+	elements []interface{}
+	// printing is set while the tuple is being converted to a string, so a
+	// tuple that contains itself through a list prints as (...) the second
+	// time.
+	printing bool
+}
+
+func NewTuple(elements []interface{}) *Tuple {
+	return &Tuple{
+		elements: elements,
+	}
+}
+
+func (t *Tuple) Get(interpreter *Interpreter, name *Token) (interface{}, error) {
+	switch name.lexeme {
+	case "len":
+		return NewNativeFunction("len", 0, 0, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+			return len(t.elements), nil
+		}), nil
+	}
+	return nil, fmt.Errorf("undefined property '%s'", name.lexeme)
+}
+
+func (t *Tuple) GetIndex(interpreter *Interpreter, index interface{}) (interface{}, error) {
+	i, err := sequenceIndex("Tuple", index, len(t.elements))
+	if err != nil {
+		return nil, err
+	}
+	return t.elements[i], nil
+}
+
+func (t *Tuple) SetIndex(interpreter *Interpreter, index interface{}, value interface{}) error {
+	return fmt.Errorf("Tuples are immutable.")
+}
+
+func (t *Tuple) String() string {
+	if t.printing {
+		return "(...)"
+	}
+	t.printing = true
+	defer func() { t.printing = false }()
+	parts := make([]string, len(t.elements))
+	for i, element := range t.elements {
+		parts[i] = stringify(element)
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}